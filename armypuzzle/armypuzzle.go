@@ -3,7 +3,12 @@
 package main
 
 import (
+	"encoding/csv"
+	"flag"
 	"fmt"
+	"log"
+	"os"
+	"slices"
 	"strconv"
 	"strings"
 )
@@ -15,35 +20,240 @@ type Regiment struct {
 
 type Army struct {
 	regiments []*Regiment
+
+	// roster lists all regiments, including those that have shipped out
+	roster []*Regiment
+
+	// history has the strengths of the regiments that have not shipped
+	// out yet, by regiment number, at the start and in each week
+	history []map[int]int
+
+	// reinforce decides how many men join each regiment every week
+	reinforce Reinforcements
+
+	// tieRule names the rule in tieBreaks that decides which regiment
+	// ships out when several have the most men
+	tieRule string
 }
 
-func (a *Army) solve() {
-	reportRegimentStatus(a.regiments)
+var (
+	csvPath  string
+	schedule string
+	tieRule  string
+	quiet    bool
+	verbose  bool
+)
 
-	weekRegiment5goes := 0
-	for week := 1; week <= 20; week++ {
-		a.update()
-		pos, biggest := a.biggestRegiment()
-		a.shipout(pos)
+// Reinforcements returns the number of men that join the given regiment
+// in the given week.
+type Reinforcements func(week int, r *Regiment) int
+
+// PuzzleReinforcements are the reinforcements of the original puzzle:
+// 100 men a week for every regiment except regiment 5, which gets 30.
+func PuzzleReinforcements() Reinforcements {
+	return func(week int, r *Regiment) int {
+		if r.number == 5 {
+			return 30
+		}
+		return 100
+	}
+}
+
+// Schedule gives every regiment the given number of men in each week,
+// starting with week 1. The last amount carries on for later weeks.
+func Schedule(amounts ...int) Reinforcements {
+	return func(week int, r *Regiment) int {
+		if len(amounts) == 0 {
+			return 0
+		}
+		return amounts[min(week, len(amounts))-1]
+	}
+}
+
+// parseSchedule parses a comma-separated list of weekly amounts.
+func parseSchedule(s string) (Reinforcements, error) {
+	var amounts []int
+	for _, field := range strings.Split(s, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(field))
+		if err != nil {
+			return nil, fmt.Errorf("Invalid reinforcement schedule [%v]: %v", s, err)
+		}
+		amounts = append(amounts, n)
+	}
+	return Schedule(amounts...), nil
+}
+
+// TieBreak picks the regiment to ship out from the regiments that are
+// tied for the most men, which are in the order they are listed in.
+type TieBreak func(tied []*Regiment) *Regiment
+
+// tieBreaks are the rules for breaking ties that can be chosen with the
+// -tiebreak option, by name.
+var tieBreaks = map[string]TieBreak{
+	"lowest-number": func(tied []*Regiment) *Regiment {
+		return slices.MinFunc(tied, func(a, b *Regiment) int { return a.number - b.number })
+	},
+	"highest-number": func(tied []*Regiment) *Regiment {
+		return slices.MaxFunc(tied, func(a, b *Regiment) int { return a.number - b.number })
+	},
+}
+
+// Tie describes a week in which several regiments had the most men and
+// the rule that picked the one that shipped out.
+type Tie struct {
+	Regiments []*Regiment
+	Rule      string
+}
+
+// StopCondition reports whether to stop shipping out regiments after
+// the given week, in which the given regiment was shipped out.
+type StopCondition func(a *Army, week int, shippedOut *Regiment) bool
+
+// AllShippedOut stops once there are no more regiments to ship out.
+func AllShippedOut() StopCondition {
+	return func(a *Army, week int, shippedOut *Regiment) bool {
+		return len(a.regiments) == 0
+	}
+}
+
+// RegimentShipsOut stops once the regiment with the given number ships out.
+func RegimentShipsOut(number int) StopCondition {
+	return func(a *Army, week int, shippedOut *Regiment) bool {
+		return shippedOut.number == number
+	}
+}
+
+// MaxWeeks stops after the given number of weeks.
+func MaxWeeks(n int) StopCondition {
+	return func(a *Army, week int, shippedOut *Regiment) bool {
+		return week >= n
+	}
+}
+
+// AnyOf stops as soon as any of the given conditions is met.
+func AnyOf(conds ...StopCondition) StopCondition {
+	return func(a *Army, week int, shippedOut *Regiment) bool {
+		for _, stop := range conds {
+			if stop(a, week, shippedOut) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// WeekReport reports on a week of the puzzle, in which the given regiment
+// shipped out, leaving the given regiments. The tie is nil unless the
+// regiment was picked from several that had the most men.
+type WeekReport func(week int, shippedOut *Regiment, tie *Tie, regiments []*Regiment)
+
+// DetailedReport reports which regiment shipped out each week, and how
+// it won any tie, along with the strengths of the regiments that are left.
+func DetailedReport(week int, shippedOut *Regiment, tie *Tie, regiments []*Regiment) {
+	reportWeekStatus(week, shippedOut, tie)
+	reportRegimentStatus(regiments)
+}
+
+// ProgressReport reports each week on a single line that is rewritten
+// using a carriage return.
+func ProgressReport(week int, shippedOut *Regiment, tie *Tie, regiments []*Regiment) {
+	fmt.Printf("\rWeek %v: regiment %v shipped out, %v left   ", week,
+		shippedOut.number, len(regiments))
+}
 
-		reportWeekStatus(week, biggest)
-		reportRegimentStatus(a.regiments)
+// solve ships out a regiment every week until the stop condition is met
+// or there are no more regiments left to ship out, calling report after
+// each week. It returns the week regiment 5 shipped out, or 0 if it
+// hasn't.
+func (a *Army) solve(stop StopCondition, report WeekReport) (weekRegiment5goes int) {
+	stop = AnyOf(stop, AllShippedOut())
+	a.snapshot()
+	for week := 1; ; week++ {
+		a.update(week)
+		a.snapshot()
+		pos, biggest, tie := a.biggestRegiment()
+		a.shipout(pos)
+		report(week, biggest, tie, a.regiments)
 
 		if biggest.number == 5 {
 			weekRegiment5goes = week
 		}
+		if stop(a, week, biggest) {
+			return
+		}
+	}
+}
+
+func reportAnswer(weekRegiment5goes int) {
+	if weekRegiment5goes == 0 {
+		fmt.Printf("\nAnswer: Regiment 5 has not shipped out yet\n")
+		return
 	}
 	fmt.Printf("\nAnswer: Regiment 5 waits %v weeks to ship out\n", weekRegiment5goes)
 }
 
+// snapshot records the strengths of the regiments that have not
+// shipped out yet.
+func (a *Army) snapshot() {
+	strengths := map[int]int{}
+	for _, r := range a.regiments {
+		strengths[r.number] = r.strength
+	}
+	a.history = append(a.history, strengths)
+}
+
+// writeCSV writes the recorded history of regiment strengths to a CSV
+// file, one row per week and one column per regiment. A regiment's
+// strength is blank for the weeks after it shipped out.
+func (a *Army) writeCSV(path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	w := csv.NewWriter(file)
+	header := []string{"Week"}
+	for _, r := range a.roster {
+		header = append(header, fmt.Sprintf("%v %v", r.number, r.name))
+	}
+	w.Write(header)
+	for week, strengths := range a.history {
+		row := []string{strconv.Itoa(week)}
+		for _, r := range a.roster {
+			s, ok := strengths[r.number]
+			if ok {
+				row = append(row, strconv.Itoa(s))
+			} else {
+				row = append(row, "")
+			}
+		}
+		w.Write(row)
+	}
+	w.Flush()
+	return w.Error()
+}
+
 func (a *Army) shipout(r int) {
 	a.regiments = append(a.regiments[:r], a.regiments[r+1:]...)
 }
 
-func reportWeekStatus(w int, shippedOut *Regiment) {
+func reportWeekStatus(w int, shippedOut *Regiment, tie *Tie) {
 	fmt.Printf("\nWeek %d\n", w)
 	fmt.Printf("Regiment %v (%v) with %v men shipped out\n", shippedOut.number,
 		shippedOut.name, shippedOut.strength)
+	if tie != nil {
+		fmt.Println(tie)
+	}
+}
+
+func (t *Tie) String() string {
+	numbers := make([]string, len(t.Regiments))
+	for i, r := range t.Regiments {
+		numbers[i] = strconv.Itoa(r.number)
+	}
+	return fmt.Sprintf("(tie between regiments %v broken by %v rule)",
+		strings.Join(numbers, ", "), t.Rule)
 }
 
 func reportRegimentStatus(regiments []*Regiment) {
@@ -62,25 +272,57 @@ func reportRegimentStatus(regiments []*Regiment) {
 	}
 }
 
-func (a *Army) update() {
+// update reinforces the regiments that haven't shipped out yet for the
+// given week.
+func (a *Army) update(week int) {
 	for _, r := range a.regiments {
-		if r.number == 5 {
-			r.strength += 30
-		} else {
-			r.strength += 100
-		}
+		r.strength += a.reinforce(week, r)
 	}
 }
 
-func (a *Army) biggestRegiment() (pos int, mostMen *Regiment) {
-	pos, mostMen = 0, a.regiments[0]
-	for i, r := range a.regiments {
-		if r.strength > mostMen.strength {
-			pos = i
-			mostMen = r
+// biggestRegiment returns the regiment with the most men and its position.
+// If several regiments have the most men, the tie-break rule picks one of
+// them and the tie is returned too.
+func (a *Army) biggestRegiment() (pos int, mostMen *Regiment, tie *Tie) {
+	most := slices.MaxFunc(a.regiments, func(r1, r2 *Regiment) int {
+		return r1.strength - r2.strength
+	}).strength
+	var tied []*Regiment
+	for _, r := range a.regiments {
+		if r.strength == most {
+			tied = append(tied, r)
 		}
 	}
-	return
+	mostMen = tied[0]
+	if len(tied) > 1 {
+		mostMen = tieBreaks[a.tieRule](tied)
+		tie = &Tie{Regiments: tied, Rule: a.tieRule}
+	}
+	return slices.Index(a.regiments, mostMen), mostMen, tie
+}
+
+// puzzleRegiments are the regiments of the puzzle, strongest first.
+var puzzleRegiments = []string{
+	"1 Aardvarks",
+	"2 Begonias",
+	"3 Chrysanthemums",
+	"4 Dhalias",
+	"5 Elephants",
+	"6 Ferrets",
+	"7 GilaMonsters",
+	"8 Hyraxes",
+	"9 Ibex",
+	"10 Jackyls",
+	"11 KimodoDragons",
+	"12 Lemurs",
+	"13 Marigolds",
+	"14 Nonames",
+	"15 Opossums",
+	"16 Porcupines",
+	"17 Quahogs",
+	"18 Rhododendrons",
+	"19 Swordfish",
+	"20 Tapirs",
 }
 
 func NewArmy(regimentList []string) *Army {
@@ -92,31 +334,60 @@ func NewArmy(regimentList []string) *Army {
 		regs[i] = &Regiment{number: num, name: parts[1], strength: strength}
 		strength -= 50
 	}
-	return &Army{regiments: regs}
+	return &Army{regiments: regs, roster: append([]*Regiment{}, regs...),
+		reinforce: PuzzleReinforcements(), tieRule: "lowest-number"}
+}
+
+func init() {
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %v [-quiet | -verbose] [-csv] [-schedule] [-tiebreak]\n\n"+
+			"Options:\n\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+
+	flag.BoolVar(&quiet, "quiet", false, "only show the progress of the weeks and the answer")
+	flag.BoolVar(&verbose, "verbose", false, "show the regiments that are left every week (default)")
+	flag.StringVar(&csvPath, "csv", "", "write the weekly strengths of the regiments to `file`")
+	flag.StringVar(&tieRule, "tiebreak", "lowest-number", "`rule` for which of the regiments with the most men ships out:\n\t"+
+		"lowest-number or highest-number")
+	flag.StringVar(&schedule, "schedule", "", "reinforce every regiment by the comma-separated `amounts` in each week\n\t"+
+		"the last amount carries on for later weeks (default 100 men, 30 for regiment 5)")
 }
 
 func main() {
-	army := NewArmy([]string{
-		"1 Aardvarks",
-		"2 Begonias",
-		"3 Chrysanthemums",
-		"4 Dhalias",
-		"5 Elephants",
-		"6 Ferrets",
-		"7 GilaMonsters",
-		"8 Hyraxes",
-		"9 Ibex",
-		"10 Jackyls",
-		"11 KimodoDragons",
-		"12 Lemurs",
-		"13 Marigolds",
-		"14 Nonames",
-		"15 Opossums",
-		"16 Porcupines",
-		"17 Quahogs",
-		"18 Rhododendrons",
-		"19 Swordfish",
-		"20 Tapirs",
-	})
-	army.solve()
+	flag.Parse()
+
+	army := NewArmy(puzzleRegiments)
+	if schedule != "" {
+		reinforce, err := parseSchedule(schedule)
+		if err != nil {
+			log.Fatal(err)
+		}
+		army.reinforce = reinforce
+	}
+	if _, ok := tieBreaks[tieRule]; !ok {
+		log.Fatalf("Unknown tie-break rule [%v]", tieRule)
+	}
+	army.tieRule = tieRule
+	if quiet && verbose {
+		fmt.Fprintln(os.Stderr, "Cannot be both -quiet and -verbose")
+		os.Exit(2)
+	}
+	report := DetailedReport
+	if quiet {
+		report = ProgressReport
+	} else {
+		reportRegimentStatus(army.regiments)
+	}
+	week := army.solve(RegimentShipsOut(5), report)
+	if quiet {
+		fmt.Println()
+	}
+	reportAnswer(week)
+
+	if csvPath != "" {
+		if err := army.writeCSV(csvPath); err != nil {
+			log.Fatal(err)
+		}
+	}
 }
@@ -0,0 +1,101 @@
+package main
+
+import "testing"
+
+// weeks returns a WeekReport that records the regiments shipped out
+// each week.
+func weeks(shipped *[]int) WeekReport {
+	return func(week int, shippedOut *Regiment, tie *Tie, regiments []*Regiment) {
+		*shipped = append(*shipped, shippedOut.number)
+	}
+}
+
+func TestSolveUntilAllShippedOut(t *testing.T) {
+	a := NewArmy([]string{"1 Aardvarks", "2 Begonias", "3 Chrysanthemums"})
+	var shipped []int
+	a.solve(AllShippedOut(), weeks(&shipped))
+	if len(shipped) != 3 || len(a.regiments) != 0 {
+		t.Errorf("shipped out %v leaving %v regiments, want all 3 shipped out", shipped, len(a.regiments))
+	}
+}
+
+func TestSolveUntilRegimentShipsOut(t *testing.T) {
+	a := NewArmy(puzzleRegiments)
+	var shipped []int
+	a.solve(RegimentShipsOut(6), weeks(&shipped))
+	if len(shipped) != 5 || shipped[4] != 6 {
+		t.Errorf("shipped out %v, want regiment 6 last in week 5", shipped)
+	}
+}
+
+func TestSolveUntilMaxWeeks(t *testing.T) {
+	a := NewArmy(puzzleRegiments)
+	var shipped []int
+	week5 := a.solve(MaxWeeks(4), weeks(&shipped))
+	if len(shipped) != 4 || len(a.regiments) != len(puzzleRegiments)-4 || week5 != 0 {
+		t.Errorf("shipped out %v leaving %v regiments (regiment 5 in week %v), want 4 weeks",
+			shipped, len(a.regiments), week5)
+	}
+}
+
+func TestSolvePuzzle(t *testing.T) {
+	a := NewArmy(puzzleRegiments)
+	var shipped []int
+	if week := a.solve(RegimentShipsOut(5), weeks(&shipped)); week != 20 {
+		t.Errorf("regiment 5 shipped out in week %v, want 20", week)
+	}
+}
+
+func TestScheduleReinforcements(t *testing.T) {
+	a := NewArmy([]string{"1 Aardvarks", "2 Begonias", "3 Chrysanthemums"})
+	a.reinforce = Schedule(10, 20)
+	for week := 1; week <= 3; week++ {
+		a.update(week)
+	}
+	want := []int{200, 150, 100}
+	for i, r := range a.regiments {
+		if r.strength != want[i] {
+			t.Errorf("regiment %v has %v men after 3 weeks, want %v", r.number, r.strength, want[i])
+		}
+	}
+}
+
+func TestScheduleChangesTheAnswer(t *testing.T) {
+	// With every regiment reinforced alike, they ship out strongest first.
+	a := NewArmy(puzzleRegiments)
+	a.reinforce = Schedule(100)
+	var shipped []int
+	if week := a.solve(RegimentShipsOut(5), weeks(&shipped)); week != 5 {
+		t.Errorf("regiment 5 shipped out in week %v, want 5", week)
+	}
+}
+
+func TestBiggestRegimentBreaksTies(t *testing.T) {
+	tests := []struct {
+		rule   string
+		number int
+		note   string
+	}{
+		{"lowest-number", 2, "(tie between regiments 2, 3 broken by lowest-number rule)"},
+		{"highest-number", 3, "(tie between regiments 2, 3 broken by highest-number rule)"},
+	}
+	for _, tt := range tests {
+		a := &Army{regiments: []*Regiment{{"A", 1, 100}, {"B", 2, 300}, {"C", 3, 300}}, tieRule: tt.rule}
+		pos, biggest, tie := a.biggestRegiment()
+		if biggest.number != tt.number || pos != tt.number-1 {
+			t.Errorf("%v: picked regiment %v at %v, want %v", tt.rule, biggest.number, pos, tt.number)
+		}
+		if tie == nil {
+			t.Errorf("%v: no tie reported", tt.rule)
+		} else if got := tie.String(); got != tt.note {
+			t.Errorf("%v: tie reported as %q, want %q", tt.rule, got, tt.note)
+		}
+	}
+}
+
+func TestBiggestRegimentWithoutATie(t *testing.T) {
+	a := &Army{regiments: []*Regiment{{"A", 1, 100}, {"B", 2, 300}, {"C", 3, 200}}}
+	if _, biggest, tie := a.biggestRegiment(); biggest.number != 2 || tie != nil {
+		t.Errorf("picked regiment %v with tie %v, want regiment 2 and no tie", biggest.number, tie)
+	}
+}
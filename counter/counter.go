@@ -1,40 +1,205 @@
 package main
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"math"
+)
 
-type Counter int
+// Integer is the set of types that a Counter can count with.
+type Integer interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr
+}
+
+// Counter counts using values of type T. Incrementing wraps around at the
+// upper bound of T like the ++ operator does. Decrementing an unsigned
+// Counter saturates at zero instead of wrapping around to the maximum.
+type Counter[T Integer] struct {
+	v T
+}
+
+// Value returns the current count.
+func (c *Counter[T]) Value() T {
+	return c.v
+}
+
+func (c Counter[T]) String() string {
+	return fmt.Sprint(c.v)
+}
+
+// Equals reports whether c has the same count as other.
+func (c Counter[T]) Equals(other Counter[T]) bool {
+	return c.v == other.v
+}
+
+// Less reports whether c has a lower count than other.
+func (c Counter[T]) Less(other Counter[T]) bool {
+	return c.v < other.v
+}
+
+// Sub returns the difference between the counts of c and other, which is
+// negative when c is less than other, even for unsigned counters. Counts
+// that don't fit in an int give meaningless results.
+func (c Counter[T]) Sub(other Counter[T]) int {
+	return int(c.v) - int(other.v)
+}
+
+// decr decrements the count unless it is an unsigned zero.
+func (c *Counter[T]) decr() {
+	unsigned := ^T(0) > 0
+	if unsigned && c.v == 0 {
+		return
+	}
+	c.v--
+}
+
+func (c *Counter[T]) PostDecr() (v T) {
+	v = c.v
+	c.decr()
+	return
+}
+
+func (c *Counter[T]) PreDecr() (v T) {
+	c.decr()
+	return c.v
+}
+
+func (c *Counter[T]) PostIncr() (v T) {
+	v = c.v
+	c.v++
+	return
+}
+
+func (c *Counter[T]) PreIncr() T {
+	c.v++
+	return c.v
+}
+
+// incrOverflows reports whether incrementing v would wrap around.
+func incrOverflows[T Integer](v T) bool {
+	return v+1 < v
+}
+
+// decrUnderflows reports whether decrementing v would wrap around.
+func decrUnderflows[T Integer](v T) bool {
+	return v-1 > v
+}
+
+// SaturatingCounter is a Counter that clamps at the bounds of T
+// instead of wrapping around.
+type SaturatingCounter[T Integer] struct {
+	Counter[T]
+}
+
+func (c *SaturatingCounter[T]) incr() {
+	if !incrOverflows(c.v) {
+		c.v++
+	}
+}
 
-func (c *Counter) PostDecr() (v int) {
-  v = int(*c)
-  *c--
-  return
+func (c *SaturatingCounter[T]) decr() {
+	if !decrUnderflows(c.v) {
+		c.v--
+	}
+}
+
+func (c *SaturatingCounter[T]) PostDecr() (v T) {
+	v = c.v
+	c.decr()
+	return
 }
 
-func (c *Counter) PreDecr() (v int) {
-  *c--
-  return int(*c)
+func (c *SaturatingCounter[T]) PreDecr() (v T) {
+	c.decr()
+	return c.v
 }
 
-func (c *Counter) PostIncr() (v int) {
-  v = int(*c)
-  *c++
-  return
+func (c *SaturatingCounter[T]) PostIncr() (v T) {
+	v = c.v
+	c.incr()
+	return
 }
 
-func (c *Counter) PreIncr() (int) {
-  *c++
-  return int(*c)
+func (c *SaturatingCounter[T]) PreIncr() T {
+	c.incr()
+	return c.v
+}
+
+var (
+	ErrOverflow  = errors.New("counter overflow")
+	ErrUnderflow = errors.New("counter underflow")
+)
+
+// CheckedCounter is a Counter that reports an error instead of wrapping
+// around at the bounds of T. The count is left unchanged when an error
+// is returned.
+type CheckedCounter[T Integer] struct {
+	Counter[T]
+}
+
+func (c *CheckedCounter[T]) incr() error {
+	if incrOverflows(c.v) {
+		return ErrOverflow
+	}
+	c.v++
+	return nil
+}
+
+func (c *CheckedCounter[T]) decr() error {
+	if decrUnderflows(c.v) {
+		return ErrUnderflow
+	}
+	c.v--
+	return nil
+}
+
+func (c *CheckedCounter[T]) PostDecr() (v T, err error) {
+	v = c.v
+	err = c.decr()
+	return
+}
+
+func (c *CheckedCounter[T]) PreDecr() (T, error) {
+	err := c.decr()
+	return c.v, err
+}
+
+func (c *CheckedCounter[T]) PostIncr() (v T, err error) {
+	v = c.v
+	err = c.incr()
+	return
+}
+
+func (c *CheckedCounter[T]) PreIncr() (T, error) {
+	err := c.incr()
+	return c.v, err
 }
 
 func main() {
-	var hits Counter
+	var hits Counter[int]
 	fmt.Println(hits)
 	fmt.Printf("++hits: %v hits++: %v\n", hits.PreIncr(), hits.PostIncr())
 	fmt.Println(hits)
 
-	var c Counter
+	var c Counter[int]
 
-	for c < 5 {
-	   fmt.Println(c.PreIncr())
+	for c.Value() < 5 {
+		fmt.Println(c.PreIncr())
 	}
+
+	fmt.Printf("c == hits: %v c < hits: %v hits - c: %v\n", c.Equals(hits), c.Less(hits), hits.Sub(c))
+
+	var u Counter[uint8]
+	fmt.Printf("uint8 --u: %v u++: %v ++u: %v\n", u.PreDecr(), u.PostIncr(), u.PreIncr())
+
+	big := Counter[int64]{v: math.MaxInt64 - 1}
+	fmt.Printf("int64 ++big: %v\n", big.PreIncr())
+
+	sat := SaturatingCounter[int]{Counter[int]{v: math.MaxInt}}
+	fmt.Printf("saturating ++sat: %v\n", sat.PreIncr())
+
+	checked := CheckedCounter[int]{Counter[int]{v: math.MinInt}}
+	v, err := checked.PreDecr()
+	fmt.Printf("checked --checked: %v (%v)\n", v, err)
 }
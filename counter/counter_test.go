@@ -0,0 +1,113 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestUint8CounterWrapsUpAndSaturatesDown(t *testing.T) {
+	var u Counter[uint8]
+	if got := u.PreDecr(); got != 0 {
+		t.Errorf("--u from 0 = %v, want 0", got)
+	}
+	if got := u.PostDecr(); got != 0 || u.Value() != 0 {
+		t.Errorf("u-- from 0 = %v leaving %v, want 0 leaving 0", got, u.Value())
+	}
+	u = Counter[uint8]{v: math.MaxUint8}
+	if got := u.PostIncr(); got != math.MaxUint8 || u.Value() != 0 {
+		t.Errorf("u++ from %v = %v leaving %v, want %v leaving 0", math.MaxUint8, got, u.Value(), math.MaxUint8)
+	}
+	if got := u.PreIncr(); got != 1 {
+		t.Errorf("++u from 0 = %v, want 1", got)
+	}
+}
+
+func TestInt64CounterWithLargeValues(t *testing.T) {
+	c := Counter[int64]{v: math.MaxInt64 - 1}
+	if got := c.PreIncr(); got != math.MaxInt64 {
+		t.Errorf("++c = %v, want %v", got, int64(math.MaxInt64))
+	}
+	if got := c.PostIncr(); got != math.MaxInt64 || c.Value() != math.MinInt64 {
+		t.Errorf("c++ = %v leaving %v, want %v leaving %v", got, c.Value(), int64(math.MaxInt64), int64(math.MinInt64))
+	}
+	if got := c.PreDecr(); got != math.MaxInt64 {
+		t.Errorf("--c = %v, want %v", got, int64(math.MaxInt64))
+	}
+}
+
+func TestSaturatingCounterClampsAtTheBounds(t *testing.T) {
+	c := SaturatingCounter[int]{Counter[int]{v: math.MaxInt - 1}}
+	if got := c.PreIncr(); got != math.MaxInt {
+		t.Errorf("++c = %v, want %v", got, math.MaxInt)
+	}
+	if got := c.PostIncr(); got != math.MaxInt || c.Value() != math.MaxInt {
+		t.Errorf("c++ = %v leaving %v, want %v leaving %v", got, c.Value(), math.MaxInt, math.MaxInt)
+	}
+	c = SaturatingCounter[int]{Counter[int]{v: math.MinInt + 1}}
+	if got := c.PreDecr(); got != math.MinInt {
+		t.Errorf("--c = %v, want %v", got, math.MinInt)
+	}
+	if got := c.PostDecr(); got != math.MinInt || c.Value() != math.MinInt {
+		t.Errorf("c-- = %v leaving %v, want %v leaving %v", got, c.Value(), math.MinInt, math.MinInt)
+	}
+}
+
+func TestCheckedCounterReportsOverflow(t *testing.T) {
+	c := CheckedCounter[int]{Counter[int]{v: math.MaxInt - 1}}
+	if got, err := c.PreIncr(); got != math.MaxInt || err != nil {
+		t.Errorf("++c = %v, %v, want %v, nil", got, err, math.MaxInt)
+	}
+	if got, err := c.PreIncr(); got != math.MaxInt || err != ErrOverflow {
+		t.Errorf("++c = %v, %v, want %v, %v", got, err, math.MaxInt, ErrOverflow)
+	}
+	if got, err := c.PostIncr(); got != math.MaxInt || err != ErrOverflow || c.Value() != math.MaxInt {
+		t.Errorf("c++ = %v, %v leaving %v, want %v, %v leaving %v", got, err, c.Value(), math.MaxInt, ErrOverflow, math.MaxInt)
+	}
+}
+
+func TestCheckedCounterReportsUnderflow(t *testing.T) {
+	c := CheckedCounter[int]{Counter[int]{v: math.MinInt + 1}}
+	if got, err := c.PostDecr(); got != math.MinInt+1 || err != nil {
+		t.Errorf("c-- = %v, %v, want %v, nil", got, err, math.MinInt+1)
+	}
+	if got, err := c.PreDecr(); got != math.MinInt || err != ErrUnderflow {
+		t.Errorf("--c = %v, %v, want %v, %v", got, err, math.MinInt, ErrUnderflow)
+	}
+	var u CheckedCounter[uint]
+	if got, err := u.PreDecr(); got != 0 || err != ErrUnderflow {
+		t.Errorf("--u from 0 = %v, %v, want 0, %v", got, err, ErrUnderflow)
+	}
+}
+
+func TestCounterComparisons(t *testing.T) {
+	two, three := Counter[int]{v: 2}, Counter[int]{v: 3}
+	tests := []struct {
+		name   string
+		a, b   Counter[int]
+		equals bool
+		less   bool
+		sub    int
+	}{
+		{"equal", two, two, true, false, 0},
+		{"less", two, three, false, true, -1},
+		{"greater", three, two, false, false, 1},
+	}
+	for _, tt := range tests {
+		if got := tt.a.Equals(tt.b); got != tt.equals {
+			t.Errorf("%v: %v.Equals(%v) = %v, want %v", tt.name, tt.a, tt.b, got, tt.equals)
+		}
+		if got := tt.a.Less(tt.b); got != tt.less {
+			t.Errorf("%v: %v.Less(%v) = %v, want %v", tt.name, tt.a, tt.b, got, tt.less)
+		}
+		if got := tt.a.Sub(tt.b); got != tt.sub {
+			t.Errorf("%v: %v.Sub(%v) = %v, want %v", tt.name, tt.a, tt.b, got, tt.sub)
+		}
+	}
+}
+
+func TestUnsignedCounterSubIsNegative(t *testing.T) {
+	a, b := Counter[uint8]{v: 3}, Counter[uint8]{v: 200}
+	if got := a.Sub(b); got != -197 {
+		t.Errorf("%v.Sub(%v) = %v, want -197", a, b, got)
+	}
+}
@@ -3,43 +3,133 @@ package main
 import (
 	"flag"
 	"fmt"
+	"io"
 	"os"
+	"strconv"
 )
 
-var n int
+var (
+	n      int
+	a, b   uint64
+	ratios bool
+	trace  bool
+	cols   int
+)
 
 // fib returns a closure that generates the fibonacci series
 func fib() func() uint64 {
-	var fib0, fib1 uint64 = 0, 1
+	return fibFrom(0, 1)
+}
+
+// fibFrom returns a closure that generates the additive series that
+// starts with a and b, e.g. the Lucas numbers start with 2 and 1.
+func fibFrom(a, b uint64) func() uint64 {
+	var fib0, fib1 uint64 = a, b
 	return func() (f uint64) {
 		f, fib0, fib1 = fib0, fib1, fib0+fib1
 		return
 	}
 }
 
+// tracedFibFrom is like fibFrom but the closure it returns also writes
+// the values of fib0 and fib1 before and after each call to w, showing
+// how the tuple assignment advances the state of the series.
+func tracedFibFrom(a, b uint64, w io.Writer) func() uint64 {
+	var fib0, fib1 uint64 = a, b
+	return func() (f uint64) {
+		before0, before1 := fib0, fib1
+		f, fib0, fib1 = fib0, fib1, fib0+fib1
+		fmt.Fprintf(w, "  [fib0=%v fib1=%v] f, fib0, fib1 = fib0, fib1, fib0+fib1 "+
+			"[f=%v fib0=%v fib1=%v]\n", before0, before1, f, fib0, fib1)
+		return
+	}
+}
+
+// plain formats each number of a series on its own.
+func plain(fn func() uint64) func() string {
+	return func() string {
+		return strconv.FormatUint(fn(), 10)
+	}
+}
+
+// withRatios formats each number of a series along with its index and
+// its ratio to the previous number. For the Fibonacci series, the ratio
+// converges to the golden ratio.
+func withRatios(fn func() uint64) func() string {
+	i, prev := -1, uint64(0)
+	return func() string {
+		f := fn()
+		i++
+		line := fmt.Sprintf("%3d: %v", i, f)
+		if prev != 0 {
+			line += fmt.Sprintf(" (ratio %.10f)", float64(f)/float64(prev))
+		}
+		prev = f
+		return line
+	}
+}
+
 func init() {
 	flag.Usage = func() {
-		fmt.Fprintf(os.Stderr, "Usage: %v [-n]\n\n"+
+		fmt.Fprintf(os.Stderr, "Usage: %v [-n] [-a] [-b] [-ratios] [-trace] [-cols]\n\n"+
 			"Options:\n\n", os.Args[0])
 		flag.PrintDefaults()
 	}
 
 	flag.IntVar(&n, "n", 10, "print first `N` numbers of the Fibonacci series")
-	flag.Parse()
+	flag.Uint64Var(&a, "a", 0, "first number of the series")
+	flag.Uint64Var(&b, "b", 1, "second number of the series")
+	flag.BoolVar(&trace, "trace", false, "show how each call to the generator changes its state")
+	flag.BoolVar(&ratios, "ratios", false, "show the index of each number and its ratio to the previous one")
+	flag.IntVar(&cols, "cols", 1, "print `N` numbers to a line, padded to the width of the widest")
 }
 
-func printSeries(heading string, times int, fn func() uint64) {
-	fmt.Printf("\n%v:\n", heading)
-	for i := 0; i < times; i++ {
-		fmt.Println(fn())
+// printSeries writes the next times numbers of a series to w, cols to
+// a line. With more than one to a line, the numbers are padded to the
+// width of the widest so that they line up.
+func printSeries(w io.Writer, heading string, times, cols int, fn func() string) {
+	fmt.Fprintf(w, "\n%v:\n", heading)
+	if cols <= 1 {
+		for i := 0; i < times; i++ {
+			fmt.Fprintln(w, fn())
+		}
+		return
+	}
+
+	nums := make([]string, times)
+	width := 0
+	for i := range nums {
+		nums[i] = fn()
+		width = max(width, len(nums[i]))
+	}
+	for i, num := range nums {
+		fmt.Fprintf(w, "%*v, ", width, num)
+		if (i+1)%cols == 0 {
+			fmt.Fprint(w, "\n")
+		}
+	}
+	if times%cols != 0 {
+		fmt.Fprint(w, "\n")
 	}
 }
 
 func main() {
-	f, g := fib(), fib()
+	flag.Parse()
+
+	format := plain
+	if ratios {
+		format = withRatios
+	}
+	gen := fibFrom
+	if trace {
+		gen = func(a, b uint64) func() uint64 {
+			return tracedFibFrom(a, b, os.Stdout)
+		}
+	}
+	f, g := format(gen(a, b)), format(gen(a, b))
 
-	printSeries("First series", n, f)
-	printSeries("Second series", n+1, g)
-	printSeries("Continue first series", n, f)
-	printSeries("Continue second series", n, g)
+	printSeries(os.Stdout, "First series", n, cols, f)
+	printSeries(os.Stdout, "Second series", n+1, cols, g)
+	printSeries(os.Stdout, "Continue first series", n, cols, f)
+	printSeries(os.Stdout, "Continue second series", n, cols, g)
 }
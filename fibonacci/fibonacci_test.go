@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"testing"
+)
+
+// take returns the next n numbers generated by fn.
+func take(fn func() uint64, n int) []uint64 {
+	nums := make([]uint64, n)
+	for i := range nums {
+		nums[i] = fn()
+	}
+	return nums
+}
+
+func TestFibFromLucas(t *testing.T) {
+	want := []uint64{2, 1, 3, 4, 7, 11, 18}
+	if got := take(fibFrom(2, 1), len(want)); !slices.Equal(got, want) {
+		t.Errorf("fibFrom(2, 1) generated %v, want %v", got, want)
+	}
+}
+
+func TestFibStartsWithZeroAndOne(t *testing.T) {
+	want := []uint64{0, 1, 1, 2, 3, 5, 8, 13}
+	if got := take(fib(), len(want)); !slices.Equal(got, want) {
+		t.Errorf("fib() generated %v, want %v", got, want)
+	}
+}
+
+func TestRatiosConvergeToTheGoldenRatio(t *testing.T) {
+	const golden = 1.6180339887
+	next := withRatios(fib())
+	var line string
+	for range 21 {
+		line = next()
+	}
+	var i int
+	var f uint64
+	var ratio float64
+	if _, err := fmt.Sscanf(line, "%d: %d (ratio %f)", &i, &f, &ratio); err != nil {
+		t.Fatalf("Could not read the ratio from %q: %v", line, err)
+	}
+	if i != 20 || f != 6765 || math.Abs(ratio-golden) > 1e-6 {
+		t.Errorf("after 20 terms got %q, want F(20) = 6765 with a ratio within 1e-6 of %v", line, golden)
+	}
+}
@@ -0,0 +1,3 @@
+module github.com/jlacar/golang-learn
+
+go 1.24
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// activityShades show how often a cell changed, from never to the most
+// often of all the cells.
+var activityShades = []string{" ", "░", "▒", "▓", "█"}
+
+// activityMap counts how many times each cell of a Field changed state
+// over a run, for the -activity option.
+type activityMap [][]int
+
+func newActivityMap(w, h int) activityMap {
+	a := make(activityMap, h)
+	for i := range a {
+		a[i] = make([]int, w)
+	}
+	return a
+}
+
+// record counts a change of state of the cell at x, y.
+func (a activityMap) record(x, y int) {
+	a[y][x]++
+}
+
+// writeReport writes how many cells changed at least once and a map of
+// the field where the more often a cell changed, the darker its shade.
+func (a activityMap) writeReport(w io.Writer) {
+	changed, most, total := 0, 0, 0
+	for _, row := range a {
+		for _, n := range row {
+			if n > 0 {
+				changed++
+			}
+			most = max(most, n)
+			total++
+		}
+	}
+	fmt.Fprintf(w, "Activity: %v cells changed, %v never changed\n", changed, total-changed)
+	if most == 0 {
+		return
+	}
+	// cells that changed are shaded in proportion to the most changes,
+	// rounded up so that the most active cells are the darkest
+	shades := len(activityShades) - 1
+	var sb strings.Builder
+	for _, row := range a {
+		for _, n := range row {
+			shade := activityShades[(n*shades+most-1)/most]
+			sb.WriteString(strings.Repeat(shade, cellWidth))
+		}
+		sb.WriteByte('\n')
+	}
+	fmt.Fprintf(w, "%v\n", sb.String())
+}
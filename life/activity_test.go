@@ -0,0 +1,28 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestActivityShades(t *testing.T) {
+	defer func(w int) { cellWidth = w }(cellWidth)
+	cellWidth = 1
+	tests := []struct {
+		counts []int
+		want   string
+	}{
+		{[]int{0, 1, 2, 3, 4, 5, 6, 7, 8}, " ░░▒▒▓▓██"},
+		{[]int{0, 1}, " █"},
+		{[]int{0, 1, 2}, " ▒█"},
+	}
+	for _, tt := range tests {
+		a := activityMap{tt.counts}
+		var sb strings.Builder
+		a.writeReport(&sb)
+		lines := strings.Split(sb.String(), "\n")
+		if got := lines[1]; got != tt.want {
+			t.Errorf("activity %v shaded %q, want %q", tt.counts, got, tt.want)
+		}
+	}
+}
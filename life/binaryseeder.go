@@ -0,0 +1,74 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strings"
+)
+
+// BinaryLocationProvider is a LocationProvider implementation that reads
+// live cell locations from rows of 0s and 1s, as displayed with the
+// -binary option. Only the first block of consecutive rows is read and
+// any other lines before it, like the generation headers, are ignored.
+type BinaryLocationProvider struct {
+	path             string
+	i, width, height int
+	locs             []FieldLocation
+}
+
+// NextLocation returns the location of the next 1
+func (b *BinaryLocationProvider) NextLocation() (loc *FieldLocation) {
+	loc = &b.locs[b.i]
+	b.i++
+	return
+}
+
+// MoreLocations returns true if there are more 1s
+func (b BinaryLocationProvider) MoreLocations() bool {
+	return b.i < len(b.locs)
+}
+
+// MinimumBounds reports the width of the longest row and the number of rows
+func (b BinaryLocationProvider) MinimumBounds() (width, height int) {
+	return b.width, b.height
+}
+
+func (b BinaryLocationProvider) String() string {
+	return fmt.Sprintf("BinaryLocationProvider: file: %v minX: %v, minY: %v", b.path, b.width, b.height)
+}
+
+// NewBinaryLocationProvider creates a BinaryLocationProvider that gets
+// its FieldLocations from the 1s in the file specified by path.
+func NewBinaryLocationProvider(path string) (*BinaryLocationProvider, error) {
+	lines, err := readLines(path)
+	if err != nil {
+		log.Println(err.Error())
+		return nil, fmt.Errorf("Could not read file [%v]", path)
+	}
+
+	b := &BinaryLocationProvider{path: path, locs: []FieldLocation{}}
+	for _, line := range lines {
+		if !isBinaryRow(line) {
+			if b.height > 0 {
+				break
+			}
+			continue
+		}
+		for x, c := range line {
+			if c == '1' {
+				b.locs = append(b.locs, FieldLocation{x, b.height})
+			}
+		}
+		b.width = max(b.width, len(line))
+		b.height++
+	}
+	if b.height == 0 {
+		return nil, fmt.Errorf("File [%v] has no rows of 0s and 1s", path)
+	}
+	return b, nil
+}
+
+// isBinaryRow reports whether line is made up of only 0s and 1s.
+func isBinaryRow(line string) bool {
+	return line != "" && strings.Trim(line, "01") == ""
+}
@@ -0,0 +1,219 @@
+package main
+
+import "sort"
+
+// cellState is the state of a single cell. Variants of Life with more
+// than one live state, like Immigration, use the different live states
+// to tell their cells apart. In the Generations variant, cells that die
+// pass through dying states, which are neither alive nor dead.
+type cellState uint8
+
+const (
+	dead    cellState = iota
+	live              // the only live state in standard Life
+	liveAlt           // the second live state in Immigration
+	dying             // the first of the dying states in Generations
+)
+
+// alive reports whether s is one of the live states.
+func (s cellState) alive() bool {
+	return s == live || s == liveAlt
+}
+
+// aged returns the state that a dying cell passes on to next, given the
+// total number of states including dead and live. The last dying state
+// passes on to dead.
+func (s cellState) aged(states int) cellState {
+	if s+1 >= dying+cellState(states-2) {
+		return dead
+	}
+	return s + 1
+}
+
+// cellStore defines how the states of the cells of a Field are stored.
+// Coordinates given to a cellStore are always within the Field.
+type cellStore interface {
+	// get returns the state of the cell at x, y.
+	get(x, y int) cellState
+
+	// put assigns a state to the cell at x, y.
+	put(x, y int, s cellState)
+
+	// clear kills all cells.
+	clear()
+
+	// eachLive calls fn with the coordinates of every live cell,
+	// in row-major order. Dying cells are not included.
+	eachLive(fn func(x, y int))
+
+	// eachCandidate calls fn with the coordinates of every cell that
+	// could possibly be alive in the next generation. Obstacles must be
+	// stored as live cells for their neighbors to be candidates.
+	eachCandidate(fn func(x, y int))
+}
+
+// denseCells stores the state of every cell of a Field. It suits fields
+// that are small or densely populated. It also counts the cells that
+// aren't dead in each row so that empty stretches of rows can be skipped.
+type denseCells struct {
+	rows     [][]cellState
+	occupied []int
+}
+
+func newDenseCells(w, h int) denseCells {
+	s := make([][]cellState, h)
+	for i := range s {
+		s[i] = make([]cellState, w)
+	}
+	return denseCells{rows: s, occupied: make([]int, h)}
+}
+
+func (d denseCells) get(x, y int) cellState {
+	return d.rows[y][x]
+}
+
+func (d denseCells) put(x, y int, s cellState) {
+	switch old := d.rows[y][x]; {
+	case old == dead && s != dead:
+		d.occupied[y]++
+	case old != dead && s == dead:
+		d.occupied[y]--
+	}
+	d.rows[y][x] = s
+}
+
+func (d denseCells) clear() {
+	for _, row := range d.rows {
+		clear(row)
+	}
+	clear(d.occupied)
+}
+
+func (d denseCells) eachLive(fn func(x, y int)) {
+	for y, row := range d.rows {
+		if d.occupied[y] == 0 {
+			continue
+		}
+		for x, s := range row {
+			if s.alive() {
+				fn(x, y)
+			}
+		}
+	}
+}
+
+// eachCandidate visits every cell in the rows that are within -range of
+// or have cells that aren't dead. Cells in other rows have no live neighbors.
+func (d denseCells) eachCandidate(fn func(x, y int)) {
+	for y, row := range d.rows {
+		if !d.nearOccupied(y) {
+			continue
+		}
+		for x := range row {
+			fn(x, y)
+		}
+	}
+}
+
+// nearOccupied reports whether row y or any row within -range of it has
+// cells that aren't dead.
+func (d denseCells) nearOccupied(y int) bool {
+	h := len(d.rows)
+	for j := -reach; j <= reach; j++ {
+		if d.occupied[(y+j+h)%h] > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// sparseCells stores only the live (and dying) cells of a Field. It suits large
+// fields that are mostly empty since the next generation only needs to
+// consider the live cells and their neighbors.
+type sparseCells struct {
+	live          map[FieldLocation]cellState
+	width, height int
+}
+
+func newSparseCells(w, h int) *sparseCells {
+	return &sparseCells{live: map[FieldLocation]cellState{}, width: w, height: h}
+}
+
+func (s *sparseCells) get(x, y int) cellState {
+	return s.live[FieldLocation{X: x, Y: y}]
+}
+
+func (s *sparseCells) put(x, y int, state cellState) {
+	if state != dead {
+		s.live[FieldLocation{X: x, Y: y}] = state
+	} else {
+		delete(s.live, FieldLocation{X: x, Y: y})
+	}
+}
+
+func (s *sparseCells) clear() {
+	clear(s.live)
+}
+
+func (s *sparseCells) eachLive(fn func(x, y int)) {
+	locs := make([]FieldLocation, 0, len(s.live))
+	for loc, state := range s.live {
+		if state.alive() {
+			locs = append(locs, loc)
+		}
+	}
+	sort.Slice(locs, func(i, j int) bool {
+		if locs[i].Y != locs[j].Y {
+			return locs[i].Y < locs[j].Y
+		}
+		return locs[i].X < locs[j].X
+	})
+	for _, loc := range locs {
+		fn(loc.X, loc.Y)
+	}
+}
+
+// eachCandidate visits the live and dying cells and their neighbors within
+// -range, wrapping toroidally at the edges of the Field.
+func (s *sparseCells) eachCandidate(fn func(x, y int)) {
+	seen := map[FieldLocation]bool{}
+	for loc := range s.live {
+		for i := -reach; i <= reach; i++ {
+			for j := -reach; j <= reach; j++ {
+				n := FieldLocation{
+					X: (loc.X + i + s.width) % s.width,
+					Y: (loc.Y + j + s.height) % s.height,
+				}
+				if !seen[n] {
+					seen[n] = true
+					fn(n.X, n.Y)
+				}
+			}
+		}
+	}
+}
+
+// overlay marks the cells of a Field that follow special rules.
+// A nil overlay marks no cells.
+type overlay [][]bool
+
+// newOverlay returns an overlay for a Field of the given width and height
+// that marks the given locations, or nil if there are none to mark.
+func newOverlay(w, h int, locs []FieldLocation) overlay {
+	if len(locs) == 0 {
+		return nil
+	}
+	o := make(overlay, h)
+	for i := range o {
+		o[i] = make([]bool, w)
+	}
+	for _, loc := range locs {
+		o[loc.Y][loc.X] = true
+	}
+	return o
+}
+
+// has reports whether the cell at x, y is marked.
+func (o overlay) has(x, y int) bool {
+	return o != nil && o[y][x]
+}
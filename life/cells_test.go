@@ -0,0 +1,75 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestDenseAndSparseCellsAgree(t *testing.T) {
+	defer func(saved bool) { sparse = saved }(sparse)
+	tests := []struct {
+		name  string
+		lines []string
+	}{
+		{"blinker", []string{"2: ###"}},
+		{"glider", gliderLines},
+	}
+	for _, tt := range tests {
+		sparse = false
+		dense := newTestLife(t, 6, 6, tt.lines...)
+		sparse = true
+		sparseLife := newTestLife(t, 6, 6, tt.lines...)
+		// long enough for the glider to wrap around the field
+		for gen := 0; gen <= 24; gen++ {
+			if d, s := dense.LiveCells(), sparseLife.LiveCells(); !slices.Equal(d, s) {
+				t.Fatalf("%v generation %v: dense cells %v, sparse cells %v", tt.name, gen, d, s)
+			}
+			dense.step()
+			sparseLife.step()
+		}
+	}
+}
+
+// benchmarkGlider steps a single glider on a field of the given size,
+// with its cells stored sparsely or not.
+func benchmarkGlider(b *testing.B, w, h int, useSparse bool) {
+	defer func(saved bool) { sparse = saved }(sparse)
+	sparse = useSparse
+	l := newTestLife(b, w, h, gliderLines...)
+	for b.Loop() {
+		l.step()
+	}
+}
+
+func BenchmarkDenseGlider10000(b *testing.B) {
+	benchmarkGlider(b, 10000, 10000, false)
+}
+
+func BenchmarkSparseGlider10000(b *testing.B) {
+	benchmarkGlider(b, 10000, 10000, true)
+}
+
+// BenchmarkDenseGliderSkippingRows steps a glider on a field with dense
+// cells, which skips the rows with no live cells nearby. Compare it with
+// BenchmarkDenseGliderEveryRow.
+func BenchmarkDenseGliderSkippingRows(b *testing.B) {
+	benchmarkGlider(b, 1000, 1000, false)
+}
+
+// BenchmarkDenseGliderEveryRow steps a glider like
+// BenchmarkDenseGliderSkippingRows but calculates the next state of every
+// cell of the field, the way it was done before rows were skipped.
+func BenchmarkDenseGliderEveryRow(b *testing.B) {
+	l := newTestLife(b, 1000, 1000, gliderLines...)
+	for b.Loop() {
+		l.nextGen.cells.clear()
+		for y := 0; y < l.height; y++ {
+			for x := 0; x < l.width; x++ {
+				if s := l.thisGen.next(x, y, &l.rule); s != dead {
+					l.nextGen.cells.put(x, y, s)
+				}
+			}
+		}
+		l.instateNextGeneration()
+	}
+}
@@ -0,0 +1,18 @@
+package main
+
+// colorModes are the settings of the -color option.
+var colorModes = []string{"auto", "always", "never"}
+
+// useColor decides whether to display colors using ANSI escape codes. The
+// -color mode can say always or never; otherwise, in auto mode, colors are
+// only written to a terminal and not if the NO_COLOR environment variable
+// is set to anything (see https://no-color.org).
+func useColor(mode string, terminal bool, noColor string) bool {
+	switch mode {
+	case "always":
+		return true
+	case "never":
+		return false
+	}
+	return terminal && noColor == ""
+}
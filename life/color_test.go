@@ -0,0 +1,23 @@
+package main
+
+import "testing"
+
+func TestUseColor(t *testing.T) {
+	tests := []struct {
+		mode     string
+		terminal bool
+		noColor  string
+		want     bool
+	}{
+		{"auto", true, "", true},
+		{"auto", false, "", false}, // piped or redirected
+		{"auto", true, "1", false}, // NO_COLOR set
+		{"always", false, "1", true},
+		{"never", true, "", false},
+	}
+	for _, tt := range tests {
+		if got := useColor(tt.mode, tt.terminal, tt.noColor); got != tt.want {
+			t.Errorf("useColor(%q, %v, %q) = %v, want %v", tt.mode, tt.terminal, tt.noColor, got, tt.want)
+		}
+	}
+}
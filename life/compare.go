@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// gap separates the boards of the two simulations being compared.
+const gap = "    "
+
+// parseCompareSeeds parses the value of the -compare option, which
+// should be two seeds separated by a comma.
+func parseCompareSeeds(s string) (a, b int64, err error) {
+	parts := strings.Split(s, ",")
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("Expected two seeds separated by a comma: [%v]", s)
+	}
+	if a, err = strconv.ParseInt(strings.TrimSpace(parts[0]), 10, 64); err != nil {
+		return
+	}
+	b, err = strconv.ParseInt(strings.TrimSpace(parts[1]), 10, 64)
+	return
+}
+
+// newRandomLife returns a new Life that is randomly populated using
+// the given seed.
+func newRandomLife(w, h int, s int64) (*Life, error) {
+	rng = rand.New(rand.NewSource(s))
+	return NewLife(w, h, NewSeeder(NewRandomLocationProvider(w, h)))
+}
+
+// sideBySide renders the boards of two games next to each other with
+// the population of each shown beneath its board. If the population of
+// the first is wider than its board, the board is padded to line up the
+// second board with its population.
+func sideBySide(a, b *Life) string {
+	var sb strings.Builder
+	labelA := "Population: " + strconv.Itoa(a.Population())
+	boardWidth := cellWidth * a.width
+	width := max(boardWidth, len(labelA))
+	padding := strings.Repeat(" ", width-boardWidth)
+	rowsA := strings.Split(strings.TrimSuffix(a.String(), "\n"), "\n")
+	rowsB := strings.Split(strings.TrimSuffix(b.String(), "\n"), "\n")
+	for i := range rowsA {
+		sb.WriteString(rowsA[i] + padding + gap + rowsB[i] + "\n")
+	}
+	fmt.Fprintf(&sb, "%-*s%v%v\n", width, labelA, gap,
+		"Population: "+strconv.Itoa(b.Population()))
+	return sb.String()
+}
+
+// simulateCompare runs two simulations of the same size, randomly
+// populated using different seeds, and displays them side by side
+// until done or ctx is cancelled.
+func simulateCompare(ctx context.Context, seedA, seedB int64) {
+	a, err := newRandomLife(fieldWidth, fieldHeight, seedA)
+	if err != nil {
+		log.Fatal(err)
+	}
+	b, err := newRandomLife(fieldWidth, fieldHeight, seedB)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Printf("\nConway's Game of Life: seed %v vs seed %v\n", seedA, seedB)
+	delay := frameDelay()
+	maxgen := gens + startGen
+	for i := 0; i < maxgen && ctx.Err() == nil; i++ {
+		if startGen <= i {
+			fmt.Printf("\n\nGeneration %v (%v of %v):\n%v", a.generation(),
+				i-startGen+1, gens, sideBySide(a, b))
+			select {
+			case <-ctx.Done():
+			case <-time.After(delay):
+			}
+		}
+		a.step()
+		b.step()
+	}
+	fmt.Printf("%v generations calculated.\n", a.genCount)
+}
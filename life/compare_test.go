@@ -0,0 +1,30 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestSideBySideLinesUpNarrowBoards(t *testing.T) {
+	a := newTestLife(t, 3, 3, "1: #")
+	b := newTestLife(t, 3, 3, "0:###")
+	lines := strings.Split(strings.TrimSuffix(sideBySide(a, b), "\n"), "\n")
+	rowsB := strings.Split(strings.TrimSuffix(b.String(), "\n"), "\n")
+	if len(lines) != len(rowsB)+1 {
+		t.Fatalf("got %v lines, want %v rows and the populations", len(lines), len(rowsB))
+	}
+	// the icons are each a single rune that takes up a single column
+	populations := lines[len(rowsB)]
+	want := strings.LastIndex(populations, "Population")
+	for i, row := range rowsB {
+		boardA, ok := strings.CutSuffix(lines[i], row)
+		if !ok {
+			t.Fatalf("line %q does not end with the second board's row %q", lines[i], row)
+		}
+		if got := utf8.RuneCountInString(boardA); got != want {
+			t.Errorf("second board starts at column %v in %q, want %v like its population in %q",
+				got, lines[i], want, populations)
+		}
+	}
+}
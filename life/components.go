@@ -0,0 +1,44 @@
+package main
+
+// componentColors are the colors, from the 256-color ANSI palette, that
+// tell the components of the -components option apart. Components share
+// colors when there are more of them than colors.
+var componentColors = []int{196, 46, 33, 226, 201, 51, 208, 141}
+
+// findComponents labels the connected groups of live cells of the current
+// generation. Cells are connected if they are neighbors, including
+// diagonally and across the edges of the field. Components are numbered
+// from 1 in row-major order of their first cells. Obstacles don't belong
+// to any component.
+func (l *Life) findComponents() (labels map[FieldLocation]int, count int) {
+	labels = map[FieldLocation]int{}
+	member := func(loc FieldLocation) bool {
+		_, labeled := labels[loc]
+		return !labeled && l.thisGen.alive(loc.X, loc.Y) && !l.thisGen.obstructed(loc.X, loc.Y)
+	}
+	for _, start := range l.LiveCells() {
+		if !member(start) {
+			continue
+		}
+		count++
+		labels[start] = count
+		pending := []FieldLocation{start}
+		for len(pending) > 0 {
+			loc := pending[len(pending)-1]
+			pending = pending[:len(pending)-1]
+			for i := -1; i <= 1; i++ {
+				for j := -1; j <= 1; j++ {
+					n := FieldLocation{
+						X: (loc.X + i + l.width) % l.width,
+						Y: (loc.Y + j + l.height) % l.height,
+					}
+					if member(n) {
+						labels[n] = count
+						pending = append(pending, n)
+					}
+				}
+			}
+		}
+	}
+	return
+}
@@ -0,0 +1,36 @@
+package main
+
+import "testing"
+
+func TestFindComponents(t *testing.T) {
+	tests := []struct {
+		name  string
+		lines []string
+		want  int
+	}{
+		{"two separated blocks", []string{"1: ##   ##", "++: ##   ##"}, 2},
+		{"diagonal neighbors", []string{"1: #", "++:  #"}, 1},
+		{"across the edge", []string{"0:#        #"}, 1},
+		{"only an obstacle", []string{"obstacle:0:#"}, 0},
+	}
+	for _, tt := range tests {
+		l := newTestLife(t, 10, 5, tt.lines...)
+		if _, count := l.findComponents(); count != tt.want {
+			t.Errorf("%v: found %v components, want %v", tt.name, count, tt.want)
+		}
+	}
+}
+
+func TestFindComponentsLabelsEachBlock(t *testing.T) {
+	l := newTestLife(t, 10, 5, "1: ##   ##", "++: ##   ##")
+	labels, _ := l.findComponents()
+	for _, loc := range l.LiveCells() {
+		want := 1
+		if loc.X > 4 {
+			want = 2
+		}
+		if labels[loc] != want {
+			t.Errorf("cell %v is in component %v, want %v", loc, labels[loc], want)
+		}
+	}
+}
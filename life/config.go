@@ -0,0 +1,82 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// notConfigurable are the flags that are left out of config files
+// because they are about the config files themselves or don't run a
+// simulation.
+var notConfigurable = map[string]bool{
+	"config":       true,
+	"write-config": true,
+	"list-icons":   true,
+}
+
+// writeConfig writes the values of all the flags to a config file as
+// name=value lines. A repeatable flag like -f or -pause-at gets a line
+// for each of its values, and none if it has none.
+func writeConfig(path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	w := bufio.NewWriter(file)
+	fmt.Fprintf(w, "# %v config\n", os.Args[0])
+	flag.VisitAll(func(f *flag.Flag) {
+		if notConfigurable[f.Name] {
+			return
+		}
+		switch v := f.Value.(type) {
+		case *pathList:
+			for _, path := range *v {
+				fmt.Fprintf(w, "%v=%v\n", f.Name, path)
+			}
+		case *genList:
+			for _, n := range *v {
+				fmt.Fprintf(w, "%v=%v\n", f.Name, n)
+			}
+		default:
+			fmt.Fprintf(w, "%v=%v\n", f.Name, f.Value)
+		}
+	})
+	return w.Flush()
+}
+
+// readConfig sets the flags from the name=value lines of a config file,
+// except for flags that were given on the command line, which take
+// precedence. Blank lines and lines that start with "#" are ignored.
+func readConfig(path string) error {
+	lines, err := readLines(path)
+	if err != nil {
+		return err
+	}
+
+	explicit := map[string]bool{}
+	flag.Visit(func(f *flag.Flag) {
+		explicit[f.Name] = true
+	})
+	for n, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		name, value, ok := strings.Cut(line, "=")
+		if !ok || notConfigurable[name] {
+			return fmt.Errorf("Invalid setting in config file [%v] line %v: %v", path, n+1, line)
+		}
+		if explicit[name] {
+			continue
+		}
+		if err := flag.Set(name, value); err != nil {
+			return fmt.Errorf("Invalid setting in config file [%v] line %v: %v", path, n+1, err)
+		}
+	}
+	return nil
+}
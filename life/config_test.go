@@ -0,0 +1,62 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// withoutTestFlags replaces the command line flags with the program's
+// own flags, leaving out the test. flags of go test, until the returned
+// function restores them.
+func withoutTestFlags() (restore func()) {
+	saved := flag.CommandLine
+	flag.CommandLine = flag.NewFlagSet(saved.Name(), flag.ContinueOnError)
+	saved.VisitAll(func(f *flag.Flag) {
+		if !strings.HasPrefix(f.Name, "test.") {
+			flag.Var(f.Value, f.Name, f.Usage)
+		}
+	})
+	return func() { flag.CommandLine = saved }
+}
+
+// flagValues returns the value of every flag, by name.
+func flagValues() map[string]string {
+	values := map[string]string{}
+	flag.VisitAll(func(f *flag.Flag) {
+		values[f.Name] = f.Value.String()
+	})
+	return values
+}
+
+func TestConfigRoundTrip(t *testing.T) {
+	defer func(w, h int, s int64, r string, p genList, f pathList) {
+		fieldWidth, fieldHeight, seed, ruleString, pauseAt, initPaths = w, h, s, r, p, f
+	}(fieldWidth, fieldHeight, seed, ruleString, pauseAt, initPaths)
+
+	for _, pauses := range []genList{nil, {3, 7}} {
+		t.Run(fmt.Sprintf("pause-at %v", pauses), func(t *testing.T) {
+			defer withoutTestFlags()()
+			fieldWidth, fieldHeight, seed, ruleString = 42, 17, 5, "B36/S23"
+			pauseAt, initPaths = pauses, pathList{"a.field", "b.field"}
+			want := flagValues()
+			path := filepath.Join(t.TempDir(), "life.cfg")
+			if err := writeConfig(path); err != nil {
+				t.Fatal(err)
+			}
+
+			fieldWidth, fieldHeight, seed, ruleString = 30, 30, 0, conway
+			pauseAt, initPaths = nil, nil
+			if err := readConfig(path); err != nil {
+				t.Fatal(err)
+			}
+			for name, value := range flagValues() {
+				if value != want[name] && !notConfigurable[name] {
+					t.Errorf("-%v is %q after reading the config back, want %q", name, value, want[name])
+				}
+			}
+		})
+	}
+}
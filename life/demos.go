@@ -0,0 +1,52 @@
+package main
+
+import (
+	"embed"
+	"fmt"
+	"maps"
+	"path"
+	"slices"
+	"strings"
+)
+
+// fieldDefs holds the field definition files that the demos use.
+//
+//go:embed field-defs/*.field
+var fieldDefs embed.FS
+
+// demo is a built-in pattern along with a field size that shows it off.
+type demo struct {
+	file          string
+	width, height int
+}
+
+var demos = map[string]demo{
+	"gosper": {"gosper-glider-gun.field", 61, 40},
+	"pulsar": {"pulsar.field", 17, 17},
+	"lwss":   {"lwss.field", 40, 16},
+}
+
+// demoNames returns the names of the demos in alphabetical order.
+func demoNames() string {
+	return strings.Join(slices.Sorted(maps.Keys(demos)), ", ")
+}
+
+// newDemoLocationProvider creates a FileLocationProvider for the named
+// demo from its embedded field definition file.
+func newDemoLocationProvider(name string) (*FileLocationProvider, demo, error) {
+	d, ok := demos[name]
+	if !ok {
+		return nil, d, fmt.Errorf("Unknown demo [%v]; try one of: %v", name, demoNames())
+	}
+	file, err := fieldDefs.Open(path.Join("field-defs", d.file))
+	if err != nil {
+		return nil, d, err
+	}
+	defer file.Close()
+	lines, err := scanLines(file)
+	if err != nil {
+		return nil, d, err
+	}
+	flp, err := parseFieldDefinition(d.file, lines)
+	return flp, d, err
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// editKeys explains the keys of the -edit option.
+const editKeys = "arrows or h/j/k/l: move  space: toggle cell  s: save  r: save and run  q: quit"
+
+// editor is a field being drawn with the -edit option, one cell at a
+// time at the cursor.
+type editor struct {
+	cells  [][]bool
+	cx, cy int
+}
+
+func newEditor(w, h int) *editor {
+	cells := make([][]bool, h)
+	for i := range cells {
+		cells[i] = make([]bool, w)
+	}
+	return &editor{cells: cells}
+}
+
+// load marks the live cells read from the field definition file at path.
+func (e *editor) load(path string) error {
+	flp, err := NewFileLocationProvider(path)
+	if err != nil {
+		return err
+	}
+	for flp.MoreLocations() {
+		loc := flp.NextLocation()
+		if loc.Y < len(e.cells) && loc.X < len(e.cells[0]) {
+			e.cells[loc.Y][loc.X] = true
+		}
+	}
+	return nil
+}
+
+// move moves the cursor by dx, dy, keeping it within the field.
+func (e *editor) move(dx, dy int) {
+	e.cx = min(max(e.cx+dx, 0), len(e.cells[0])-1)
+	e.cy = min(max(e.cy+dy, 0), len(e.cells)-1)
+}
+
+// toggle brings the cell at the cursor to life or kills it.
+func (e *editor) toggle() {
+	e.cells[e.cy][e.cx] = !e.cells[e.cy][e.cx]
+}
+
+// draw writes the field with the cursor shown in reverse video. Lines end
+// with "\r\n" since the terminal is in raw mode.
+func (e *editor) draw(w io.Writer, path string) {
+	var sb strings.Builder
+	sb.WriteString(clearScreen)
+	fmt.Fprintf(&sb, "Editing %v (%vx%v)\r\n%v\r\n\r\n", path, len(e.cells[0]), len(e.cells), editKeys)
+	for y, row := range e.cells {
+		for x, alive := range row {
+			mark := " ."
+			if alive {
+				mark = " @"
+			}
+			if x == e.cx && y == e.cy {
+				mark = " \033[7m" + mark[1:] + "\033[0m"
+			}
+			sb.WriteString(mark)
+		}
+		sb.WriteString("\r\n")
+	}
+	io.WriteString(w, sb.String())
+}
+
+// writeFieldDefinition writes the live cells as a field definition file
+// with an absolute row line for each row that has any.
+func (e *editor) writeFieldDefinition(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "# drawn with -edit on a %vx%v field\n", len(e.cells[0]), len(e.cells))
+	for y, row := range e.cells {
+		var sb strings.Builder
+		for _, alive := range row {
+			if alive {
+				sb.WriteByte('@')
+			} else {
+				sb.WriteByte(' ')
+			}
+		}
+		if marks := strings.TrimRight(sb.String(), " "); marks != "" {
+			fmt.Fprintf(bw, "%02d:%v\n", y, marks)
+		}
+	}
+	return bw.Flush()
+}
+
+// save writes the field definition file at path.
+func (e *editor) save(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("Could not write file [%v]: %v", path, err)
+	}
+	defer f.Close()
+	return e.writeFieldDefinition(f)
+}
+
+// stty runs stty with the given arguments on the terminal that is stdin
+// and returns what it wrote.
+func stty(args ...string) (string, error) {
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = os.Stdin
+	out, err := cmd.Output()
+	return strings.TrimSpace(string(out)), err
+}
+
+// editPattern lets cells be drawn on a field of the -x and -y size, which
+// starts with the cells of the file at path if there is one, until the
+// field is saved to the file or the editor is quit. It reports whether
+// the run should go on to simulate the saved field.
+func editPattern(path string) (run bool, err error) {
+	if !isTerminal(os.Stdin) || !isTerminal(os.Stdout) {
+		return false, fmt.Errorf("Cannot edit [%v] without a terminal", path)
+	}
+	e := newEditor(fieldWidth, fieldHeight)
+	if _, err := os.Stat(path); err == nil {
+		if err := e.load(path); err != nil {
+			return false, err
+		}
+	}
+
+	saved, err := stty("-g")
+	if err != nil {
+		return false, fmt.Errorf("Could not get the terminal settings: %v", err)
+	}
+	if _, err := stty("raw", "-echo"); err != nil {
+		return false, fmt.Errorf("Could not put the terminal in raw mode: %v", err)
+	}
+	defer func() {
+		stty(saved)
+		fmt.Println()
+	}()
+
+	keys := make([]byte, 8)
+	for {
+		e.draw(os.Stdout, path)
+		n, err := os.Stdin.Read(keys)
+		if err != nil {
+			return false, err
+		}
+		switch key := string(keys[:n]); key {
+		case "\033[A", "k":
+			e.move(0, -1)
+		case "\033[B", "j":
+			e.move(0, 1)
+		case "\033[C", "l":
+			e.move(1, 0)
+		case "\033[D", "h":
+			e.move(-1, 0)
+		case " ":
+			e.toggle()
+		case "s", "r":
+			return key == "r", e.save(path)
+		case "q", "\003":
+			return false, nil
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"os"
+)
+
+// Event describes how the live cells changed from one generation to the
+// next. The first Event of a run describes all of its live cells as born.
+type Event struct {
+	Generation int             `json:"generation"`
+	Born       []FieldLocation `json:"born"`
+	Died       []FieldLocation `json:"died"`
+}
+
+var eventEncoder = json.NewEncoder(os.Stdout)
+
+// recordChange notes whether the cell at x, y is born or dies in the
+// next generation, in which it will be in the given state.
+func (l *Life) recordChange(x, y int, next cellState) {
+	wasAlive, isAlive := l.thisGen.alive(x, y), next.alive()
+	switch {
+	case !wasAlive && isAlive:
+		l.changes.Born = append(l.changes.Born, *NewFieldLocation(x, y))
+	case wasAlive && !isAlive:
+		l.changes.Died = append(l.changes.Died, *NewFieldLocation(x, y))
+	}
+}
+
+// emitEvent writes the Event for the current generation as a line of JSON.
+// If first is true, all the live cells of the current generation are
+// reported as born.
+func (l *Life) emitEvent(first bool) {
+	e := l.changes
+	if first {
+		e = &Event{Born: l.LiveCells(), Died: []FieldLocation{}}
+	}
+	e.Generation = l.generation()
+	if err := eventEncoder.Encode(e); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -2,9 +2,12 @@ package main
 
 import (
 	"bufio"
+	"cmp"
 	"fmt"
+	"io"
 	"log"
 	"os"
+	"slices"
 	"strconv"
 	"strings"
 	"unicode"
@@ -16,6 +19,8 @@ type FileLocationProvider struct {
 	path             string
 	i, width, height int
 	locs             []FieldLocation
+	blackHoles       []FieldLocation
+	obstacles        []FieldLocation
 }
 
 // NextLocation returns the next FieldLocation read from the file
@@ -36,10 +41,43 @@ func (f FileLocationProvider) MinimumBounds() (width, height int) {
 	return f.width, f.height
 }
 
+// BlackHoles returns the locations of the black holes read from the file
+func (f FileLocationProvider) BlackHoles() []FieldLocation {
+	return f.blackHoles
+}
+
+// Obstacles returns the locations of the obstacles read from the file
+func (f FileLocationProvider) Obstacles() []FieldLocation {
+	return f.obstacles
+}
+
 func (f FileLocationProvider) String() string {
 	return fmt.Sprintf("FileLocationProvider: file: %v minX: %v, minY: %v", f.path, f.width, f.height)
 }
 
+// writeLocations writes the locations read from the file, sorted by row
+// and then by column, followed by the minimum bounds of a field for them.
+func (f FileLocationProvider) writeLocations(w io.Writer) {
+	fmt.Fprintf(w, "%v\n", f.path)
+	sections := []struct {
+		name string
+		locs []FieldLocation
+	}{{"Live cells", f.locs}, {"Black holes", f.blackHoles}, {"Obstacles", f.obstacles}}
+	for _, s := range sections {
+		if len(s.locs) == 0 {
+			continue
+		}
+		fmt.Fprintf(w, "%v: %v\n", s.name, len(s.locs))
+		sorted := slices.SortedFunc(slices.Values(s.locs), func(a, b FieldLocation) int {
+			return cmp.Or(cmp.Compare(a.Y, b.Y), cmp.Compare(a.X, b.X))
+		})
+		for _, l := range sorted {
+			fmt.Fprintf(w, "  %v\n", l)
+		}
+	}
+	fmt.Fprintf(w, "Minimum bounds: -x %v -y %v\n", f.width, f.height)
+}
+
 // NewFileLocationProvider creates a FileLocationProvider that gets its
 // its FieldLocations from the field definition file specified by path.
 func NewFileLocationProvider(path string) (*FileLocationProvider, error) {
@@ -49,25 +87,80 @@ func NewFileLocationProvider(path string) (*FileLocationProvider, error) {
 		log.Println(err.Error())
 		return nil, fmt.Errorf("Could not read file [%v]", path)
 	}
+	return parseFieldDefinition(path, lines)
+}
 
+// parseFieldDefinition creates a FileLocationProvider from the lines of
+// a field definition. The path is only used to identify the definition.
+func parseFieldDefinition(path string, lines []string) (*FileLocationProvider, error) {
 	if len(lines) == 0 {
 		return nil, fmt.Errorf("File [%v] is empty", path)
 	}
 
+	columnOffset = 0
 	locs := []FieldLocation{}
+	blackHoles := []FieldLocation{}
+	obstacles := []FieldLocation{}
+	block := []FieldLocation{}
+	t := transforms["none"]
 	var minX, minY int
 	row := 0
 	for _, l := range lines {
+		if name, ok := transformDirective(l); ok {
+			locs = append(locs, t.apply(block)...)
+			block = block[:0]
+			t = lookupTransform(name)
+			continue
+		}
+		if rest, ok := strings.CutPrefix(l, "blackhole:"); ok {
+			holes, lastrow := parseConfigLine(rest, row)
+			row = lastrow
+			blackHoles = append(blackHoles, holes...)
+			minY = max(minY, row)
+			continue
+		}
+		if rest, ok := strings.CutPrefix(l, "obstacle:"); ok {
+			walls, lastrow := parseConfigLine(rest, row)
+			row = lastrow
+			obstacles = append(obstacles, walls...)
+			minY = max(minY, row)
+			continue
+		}
 		morelocs, lastrow := parseConfigLine(l, row)
 		row = lastrow
 		if len(morelocs) != 0 {
-			locs = append(locs, morelocs...)
+			block = append(block, morelocs...)
 		}
 		minY = max(minY, row)
-		minX = maxCol(minX, locs)
 	}
+	locs = append(locs, t.apply(block)...)
+	if len(locs)+len(blackHoles)+len(obstacles) == 0 {
+		return nil, fmt.Errorf("File [%v] defines no cells", path)
+	}
+	minX = maxCol(maxCol(maxCol(minX, locs), blackHoles), obstacles)
+	minY = maxRow(minY, locs)
+
+	return &FileLocationProvider{path: path, locs: locs,
+		blackHoles: blackHoles, obstacles: obstacles,
+		width: minX + 1, height: minY + 1}, nil
+}
 
-	return &FileLocationProvider{path: path, locs: locs, width: minX + 1, height: minY + 1}, nil
+// transformDirective checks if the given configuration line is a
+// "transform:NAME" directive and returns the name of the transform to
+// apply to the block of locations that follows it.
+func transformDirective(configline string) (name string, ok bool) {
+	name, ok = strings.CutPrefix(configline, "transform:")
+	return strings.TrimSpace(name), ok
+}
+
+func maxRow(y int, locs []FieldLocation) (max int) {
+	max = y
+	for _, l := range locs {
+		if l.Y > max {
+			max = l.Y
+		}
+	}
+	return
 }
 
 func maxCol(x int, locs []FieldLocation) (max int) {
@@ -120,9 +213,16 @@ func parseConfigLine(configline string, lastRow int) (locs []FieldLocation, row
 
 	y, err := strconv.Atoi(header)
 
-	// ++: -- use relative row number
+	// ++: or +N: -- use row number relative to the last row
 	if header == "++" {
 		y = lastRow + 1
+	} else if skip, ok := strings.CutPrefix(header, "+"); ok {
+		n, err := strconv.Atoi(skip)
+		if err != nil || n < 1 {
+			log.Println(configline)
+			return nil, lastRow
+		}
+		y = lastRow + n
 	} else if err != nil {
 		log.Println(configline)
 		return nil, lastRow
@@ -171,9 +271,13 @@ func readLines(path string) ([]string, error) {
 		return nil, err
 	}
 	defer file.Close()
+	return scanLines(file)
+}
 
+// scanLines reads all the lines from r.
+func scanLines(r io.Reader) ([]string, error) {
 	var lines []string
-	scanner := bufio.NewScanner(file)
+	scanner := bufio.NewScanner(r)
 	for scanner.Scan() {
 		lines = append(lines, scanner.Text())
 	}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// writeFile writes a file with the given contents to a temporary
+// directory and returns its path.
+func writeFile(t *testing.T, name, contents string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestNewFileLocationProviderRejectsFilesWithoutCells(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "missing.field")
+	tests := []struct {
+		name, path, want string
+	}{
+		{"missing", missing, "Could not read file [" + missing + "]"},
+		{"empty", writeFile(t, "empty.field", ""), "is empty"},
+		{"only comments", writeFile(t, "comments.field", "# nothing\n# to see here\n"), "defines no cells"},
+	}
+	for _, tt := range tests {
+		p, err := NewFileLocationProvider(tt.path)
+		if err == nil {
+			t.Errorf("%v: got provider %v, want an error", tt.name, p)
+			continue
+		}
+		if !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%v: got error %q, want one with %q", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestRelativeRowHeaders(t *testing.T) {
+	lines := []string{
+		"2:#",   // row 2
+		"+2:#",  // two rows down: row 4
+		"+1:#",  // one row down: row 5
+		"++:#",  // the same as +1: row 6
+		"1:#",   // absolute rows still work: row 1
+		"+3:#",  // three rows below row 1: row 4
+		"+0:#",  // not a valid skip, so ignored
+		"+x:#",  // nor is this
+		"++: #", // one row below row 4: row 5, column 1
+	}
+	p, err := parseFieldDefinition("relative rows", lines)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []FieldLocation{{0, 2}, {0, 4}, {0, 5}, {0, 6}, {0, 1}, {0, 4}, {1, 5}}
+	if !slices.Equal(p.locs, want) {
+		t.Errorf("got locations %v, want %v", p.locs, want)
+	}
+	if w, h := p.MinimumBounds(); w != 2 || h != 7 {
+		t.Errorf("got minimum bounds %vx%v, want 2x7", w, h)
+	}
+}
+
+func TestBlackHoleDirective(t *testing.T) {
+	lines := []string{
+		"0:#",
+		"blackhole:2:  ##",
+		"blackhole:++: #", // rows are relative to the last one, as for cells
+		"blackhole:x:#",   // not a row, so ignored
+		"blackhole:3",     // no marks, so ignored
+		"blackholes:4:#",  // not the directive, so ignored
+	}
+	p, err := parseFieldDefinition("black holes", lines)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []FieldLocation{{0, 0}}; !slices.Equal(p.locs, want) {
+		t.Errorf("got live cells %v, want %v", p.locs, want)
+	}
+	if want := []FieldLocation{{2, 2}, {3, 2}, {1, 3}}; !slices.Equal(p.BlackHoles(), want) {
+		t.Errorf("got black holes %v, want %v", p.BlackHoles(), want)
+	}
+	if w, h := p.MinimumBounds(); w != 4 || h != 4 {
+		t.Errorf("got minimum bounds %vx%v, want 4x4", w, h)
+	}
+
+	// a field of only black holes defines cells
+	if _, err := parseFieldDefinition("only black holes", []string{"blackhole:0:#"}); err != nil {
+		t.Errorf("a field of only black holes: %v", err)
+	}
+}
+
+func TestObstacleDirective(t *testing.T) {
+	lines := []string{
+		"0:#",
+		"obstacle:1:   #",
+		"obstacle:++:   #",
+		"obstacle:x:#", // not a row, so ignored
+		"obstacle:3",   // no marks, so ignored
+	}
+	p, err := parseFieldDefinition("obstacles", lines)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []FieldLocation{{0, 0}}; !slices.Equal(p.locs, want) {
+		t.Errorf("got live cells %v, want %v", p.locs, want)
+	}
+	if want := []FieldLocation{{3, 1}, {3, 2}}; !slices.Equal(p.Obstacles(), want) {
+		t.Errorf("got obstacles %v, want %v", p.Obstacles(), want)
+	}
+	if w, h := p.MinimumBounds(); w != 4 || h != 3 {
+		t.Errorf("got minimum bounds %vx%v, want 4x3", w, h)
+	}
+
+	// obstacles start out alive
+	l, err := NewLife(4, 3, NewSeeder(p))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !l.AliveAt(3, 1) || !l.AliveAt(3, 2) || l.Population() != 3 {
+		t.Errorf("got live cells %v, want the cell and the two obstacles", l.LiveCells())
+	}
+}
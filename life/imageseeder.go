@@ -0,0 +1,267 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/png"
+	"io"
+	"log"
+	"os"
+	"strconv"
+)
+
+// darkness is the gray level below which a pixel of an image is dark
+// enough to be a live cell.
+const darkness = 0x80
+
+// ImageLocationProvider is a LocationProvider implementation that uses
+// the dark pixels of a PNG, PBM, or PGM image as live cell locations.
+type ImageLocationProvider struct {
+	path             string
+	i, width, height int
+	locs             []FieldLocation
+}
+
+// NextLocation returns the location of the next dark pixel
+func (p *ImageLocationProvider) NextLocation() (loc *FieldLocation) {
+	loc = &p.locs[p.i]
+	p.i++
+	return
+}
+
+// MoreLocations returns true if there are more dark pixels
+func (p ImageLocationProvider) MoreLocations() bool {
+	return p.i < len(p.locs)
+}
+
+// MinimumBounds reports the dimensions of the image
+func (p ImageLocationProvider) MinimumBounds() (width, height int) {
+	return p.width, p.height
+}
+
+func (p ImageLocationProvider) String() string {
+	return fmt.Sprintf("ImageLocationProvider: file: %v minX: %v, minY: %v", p.path, p.width, p.height)
+}
+
+// NewImageLocationProvider creates an ImageLocationProvider that gets
+// its FieldLocations from the dark pixels of the image file specified
+// by path. Transparent pixels are never live.
+func NewImageLocationProvider(path string) (*ImageLocationProvider, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		log.Println(err.Error())
+		return nil, fmt.Errorf("Could not read file [%v]", path)
+	}
+	defer file.Close()
+
+	img, _, err := image.Decode(file)
+	if err != nil {
+		log.Println(err.Error())
+		return nil, fmt.Errorf("Could not read image [%v]", path)
+	}
+
+	b := img.Bounds()
+	locs := []FieldLocation{}
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			c := img.At(x, y)
+			if _, _, _, a := c.RGBA(); a == 0 {
+				continue
+			}
+			if color.GrayModel.Convert(c).(color.Gray).Y < darkness {
+				locs = append(locs, FieldLocation{x - b.Min.X, y - b.Min.Y})
+			}
+		}
+	}
+	return &ImageLocationProvider{path: path, width: b.Dx(), height: b.Dy(), locs: locs}, nil
+}
+
+func init() {
+	image.RegisterFormat("pbm", "P1", decodeNetpbm, decodeNetpbmConfig)
+	image.RegisterFormat("pbm", "P4", decodeNetpbm, decodeNetpbmConfig)
+	image.RegisterFormat("pgm", "P2", decodeNetpbm, decodeNetpbmConfig)
+	image.RegisterFormat("pgm", "P5", decodeNetpbm, decodeNetpbmConfig)
+}
+
+// netpbmHeader is the header of a PBM or PGM image. The maxval of a
+// PBM image is 1.
+type netpbmHeader struct {
+	magic                 string
+	width, height, maxval int
+}
+
+func decodeNetpbmConfig(r io.Reader) (image.Config, error) {
+	h, err := readNetpbmHeader(bufio.NewReader(r))
+	if err != nil {
+		return image.Config{}, err
+	}
+	return image.Config{ColorModel: color.GrayModel, Width: h.width, Height: h.height}, nil
+}
+
+// decodeNetpbm decodes the plain (P1, P2) and raw (P4, P5) forms of
+// PBM and PGM images.
+func decodeNetpbm(r io.Reader) (image.Image, error) {
+	br := bufio.NewReader(r)
+	h, err := readNetpbmHeader(br)
+	if err != nil {
+		return nil, err
+	}
+	img := image.NewGray(image.Rect(0, 0, h.width, h.height))
+	for y := 0; y < h.height; y++ {
+		var row []int
+		switch h.magic {
+		case "P1":
+			row, err = readPlainBits(br, h.width)
+		case "P2":
+			row, err = readPlainValues(br, h.width)
+		case "P4":
+			row, err = readRawBits(br, h.width)
+		case "P5":
+			row, err = readRawValues(br, h.width, h.maxval)
+		}
+		if err != nil {
+			return nil, err
+		}
+		for x, v := range row {
+			if h.maxval == 1 {
+				// in a PBM image, 1 is black
+				v = 1 - v
+			}
+			img.SetGray(x, y, color.Gray{uint8(v * 0xff / h.maxval)})
+		}
+	}
+	return img, nil
+}
+
+func readNetpbmHeader(r *bufio.Reader) (h netpbmHeader, err error) {
+	if h.magic, err = readToken(r); err != nil {
+		return
+	}
+	if h.width, err = readInt(r); err != nil {
+		return
+	}
+	if h.height, err = readInt(r); err != nil {
+		return
+	}
+	h.maxval = 1
+	if h.magic == "P2" || h.magic == "P5" {
+		if h.maxval, err = readInt(r); err != nil {
+			return
+		}
+	}
+	if h.width <= 0 || h.height <= 0 || h.maxval <= 0 || h.maxval > 0xffff {
+		err = fmt.Errorf("Invalid %v header: %vx%v maxval %v", h.magic, h.width, h.height, h.maxval)
+	}
+	// exactly one whitespace character separates the header from raw data
+	if err == nil && (h.magic == "P4" || h.magic == "P5") {
+		_, err = r.ReadByte()
+	}
+	return
+}
+
+// readToken reads the next whitespace-separated token, skipping any
+// comments, which run from a '#' to the end of the line.
+func readToken(r *bufio.Reader) (string, error) {
+	var token []byte
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			if err == io.EOF && len(token) > 0 {
+				return string(token), nil
+			}
+			return "", err
+		}
+		switch {
+		case b == '#':
+			if _, err := r.ReadString('\n'); err != nil {
+				return "", err
+			}
+		case b == ' ' || b == '\t' || b == '\n' || b == '\r':
+			if len(token) > 0 {
+				r.UnreadByte()
+				return string(token), nil
+			}
+		default:
+			token = append(token, b)
+		}
+	}
+}
+
+func readInt(r *bufio.Reader) (int, error) {
+	token, err := readToken(r)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(token)
+}
+
+// readPlainBits reads a row of P1 pixels, which need not be separated
+// by whitespace.
+func readPlainBits(r *bufio.Reader, width int) ([]int, error) {
+	row := make([]int, 0, width)
+	for len(row) < width {
+		b, err := r.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		switch b {
+		case '0', '1':
+			row = append(row, int(b-'0'))
+		case '#':
+			if _, err := r.ReadString('\n'); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return row, nil
+}
+
+func readPlainValues(r *bufio.Reader, width int) ([]int, error) {
+	row := make([]int, width)
+	for x := range row {
+		v, err := readInt(r)
+		if err != nil {
+			return nil, err
+		}
+		row[x] = v
+	}
+	return row, nil
+}
+
+// readRawBits reads a row of P4 pixels, packed eight to a byte with
+// each row starting on a new byte.
+func readRawBits(r *bufio.Reader, width int) ([]int, error) {
+	packed := make([]byte, (width+7)/8)
+	if _, err := io.ReadFull(r, packed); err != nil {
+		return nil, err
+	}
+	row := make([]int, width)
+	for x := range row {
+		row[x] = int(packed[x/8]>>(7-x%8)) & 1
+	}
+	return row, nil
+}
+
+// readRawValues reads a row of P5 pixels, which take up two bytes each
+// when maxval is more than 255.
+func readRawValues(r *bufio.Reader, width, maxval int) ([]int, error) {
+	size := 1
+	if maxval > 0xff {
+		size = 2
+	}
+	raw := make([]byte, width*size)
+	if _, err := io.ReadFull(r, raw); err != nil {
+		return nil, err
+	}
+	row := make([]int, width)
+	for x := range row {
+		if size == 1 {
+			row[x] = int(raw[x])
+		} else {
+			row[x] = int(raw[2*x])<<8 | int(raw[2*x+1])
+		}
+	}
+	return row, nil
+}
@@ -6,12 +6,20 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"flag"
 	"fmt"
+	"hash"
+	"hash/fnv"
+	"io"
 	"log"
+	"maps"
 	"math/rand"
 	"os"
+	"os/signal"
+	"slices"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -19,7 +27,8 @@ import (
 // within a Field. This takes the distinct data points of row/column or
 // x/y and forces them into a single entity, i.e. "reifies" them.
 type FieldLocation struct {
-	X, Y int
+	X int `json:"x"`
+	Y int `json:"y"`
 }
 
 // Creates a new FieldLocation for the given row and column coordinates.
@@ -44,6 +53,20 @@ type LocationProvider interface {
 	MinimumBounds() (width, height int)
 }
 
+// BlackHoleProvider is implemented by LocationProviders that also
+// give the locations of black holes: cells that are always dead and so
+// never count as live neighbors of other cells.
+type BlackHoleProvider interface {
+	BlackHoles() []FieldLocation
+}
+
+// ObstacleProvider is implemented by LocationProviders that also give
+// the locations of obstacles: cells that are always alive, no matter how
+// many live neighbors they have, and so act as walls.
+type ObstacleProvider interface {
+	Obstacles() []FieldLocation
+}
+
 // Seeder wraps a LocationProvider and provides a template for their use
 // by the Life program.
 type Seeder struct {
@@ -77,16 +100,74 @@ var (
 	// Used to set up the initial Field population
 	seeder *Seeder
 
+	// Source of random numbers, seeded with the -seed option so that
+	// runs are reproducible
+	rng *rand.Rand
+
 	// flag option variables
 	fieldWidth  int
 	fieldHeight int
 	gens        int
-	gensPerSec  int
+	gensPerSec  float64
 	startGen    int
+	gen0        int
 	seed        int64
 	seedflag    string
-	initPath    string
+	initPaths   pathList
+	imgPath     string
+	demoName    string
+	activity    bool
+	countdown   int
+	binary      bool
+	binPath     string
 	iconName    string
+	progress    bool
+	loop        bool
+	title       string
+	sparse      bool
+	compare     string
+	quiet       bool
+	immigration bool
+	altIconName string
+	inplace     bool
+	checksum    bool
+	events      bool
+	immortal    bool
+	compact     bool
+	maxGens     int
+	listIcons   bool
+	every       int
+	states      int
+	components  bool
+	pause       time.Duration
+	configPath  string
+	writePath   string
+	ruleString  string
+	interactive bool
+	timing      bool
+	symmetry    string
+	settle      bool
+	view        string
+	cellCount   int
+	dumpLocs    bool
+	reach       int
+	pauseAt     genList
+	skipSame    bool
+	maxPop      int
+	fit         bool
+	shuffle     bool
+	editPath    string
+	serveAddr   string
+	colorMode   string
+
+	// the rule parsed from the -rule option
+	startRule rule
+
+	// lines typed during an -interactive run
+	commands <-chan string
+
+	// where the summary at the end of a run is written
+	summary io.Writer = os.Stdout
 )
 
 // RandomLocationProvider provides random FieldLocations.
@@ -107,7 +188,7 @@ func NewRandomLocationProvider(w, h int) *RandomLocationProvider {
 // that the locations provided will be unique.
 func (r *RandomLocationProvider) NextLocation() (loc *FieldLocation) {
 	r.i++
-	return NewFieldLocation(rand.Intn(r.width), rand.Intn(r.height))
+	return NewFieldLocation(rng.Intn(r.width), rng.Intn(r.height))
 }
 
 // MoreLocations reports whether a RandomLocationProvider has more locations
@@ -124,155 +205,909 @@ func (r RandomLocationProvider) MinimumBounds() (width, height int) {
 	return r.width, r.height
 }
 
+// CountedRandomLocationProvider provides a given number of distinct
+// random FieldLocations.
+type CountedRandomLocationProvider struct {
+	count         int
+	width, height int
+	given         map[FieldLocation]bool
+}
+
+// NewCountedRandomLocationProvider creates a LocationProvider that gives
+// exactly n different random locations within a Field with the given
+// dimensions. An error is returned if the Field has fewer than n cells.
+func NewCountedRandomLocationProvider(w, h, n int) (*CountedRandomLocationProvider, error) {
+	if n > w*h {
+		return nil, fmt.Errorf("Cannot place %v cells on a field of %vx%v", n, w, h)
+	}
+	return &CountedRandomLocationProvider{count: n, width: w, height: h,
+		given: make(map[FieldLocation]bool, n)}, nil
+}
+
+// NextLocation gives a random location that has not been given before.
+func (r *CountedRandomLocationProvider) NextLocation() (loc *FieldLocation) {
+	for {
+		loc = NewFieldLocation(rng.Intn(r.width), rng.Intn(r.height))
+		if !r.given[*loc] {
+			r.given[*loc] = true
+			return
+		}
+	}
+}
+
+// MoreLocations reports whether fewer locations than were asked for have
+// been given so far.
+func (r CountedRandomLocationProvider) MoreLocations() bool {
+	return len(r.given) < r.count
+}
+
+// MinimumBounds reports the minimum dimensions of a Field so that it can
+// accommodate any location that can be given by a CountedRandomLocationProvider.
+func (r CountedRandomLocationProvider) MinimumBounds() (width, height int) {
+	return r.width, r.height
+}
+
+// ShuffledLocationProvider provides distinct random FieldLocations by
+// shuffling the locations of all the cells of a Field.
+type ShuffledLocationProvider struct {
+	i, count int
+	width    int
+	order    []int
+}
+
+// NewShuffledLocationProvider creates a LocationProvider that gives the
+// first n locations of a shuffle of all the locations within a Field with
+// the given dimensions. An error is returned if the Field has fewer than
+// n cells.
+func NewShuffledLocationProvider(w, h, n int) (*ShuffledLocationProvider, error) {
+	if n > w*h {
+		return nil, fmt.Errorf("Cannot place %v cells on a field of %vx%v", n, w, h)
+	}
+	return &ShuffledLocationProvider{count: n, width: w, order: rng.Perm(w * h)}, nil
+}
+
+// NextLocation gives the next location of the shuffle.
+func (s *ShuffledLocationProvider) NextLocation() (loc *FieldLocation) {
+	n := s.order[s.i]
+	s.i++
+	return NewFieldLocation(n%s.width, n/s.width)
+}
+
+// MoreLocations reports whether fewer locations than were asked for have
+// been given so far.
+func (s ShuffledLocationProvider) MoreLocations() bool {
+	return s.i < s.count
+}
+
+// MinimumBounds reports the minimum dimensions of a Field so that it can
+// accommodate any location that can be given by a ShuffledLocationProvider.
+func (s ShuffledLocationProvider) MinimumBounds() (width, height int) {
+	return s.width, len(s.order) / s.width
+}
+
+// CompositeLocationProvider chains several LocationProviders together,
+// giving out all the locations of each provider in turn.
+type CompositeLocationProvider struct {
+	providers []LocationProvider
+}
+
+// NewCompositeLocationProvider creates a LocationProvider that overlays
+// the locations given by all the given providers.
+func NewCompositeLocationProvider(lps ...LocationProvider) *CompositeLocationProvider {
+	return &CompositeLocationProvider{providers: lps}
+}
+
+// NextLocation gives the next location of the first provider that still
+// has more locations to give.
+func (c *CompositeLocationProvider) NextLocation() (loc *FieldLocation) {
+	for _, p := range c.providers {
+		if p.MoreLocations() {
+			return p.NextLocation()
+		}
+	}
+	return nil
+}
+
+// MoreLocations reports whether any of the providers has more locations
+// to give.
+func (c *CompositeLocationProvider) MoreLocations() bool {
+	for _, p := range c.providers {
+		if p.MoreLocations() {
+			return true
+		}
+	}
+	return false
+}
+
+// BlackHoles returns the black holes of all the providers that have them.
+func (c *CompositeLocationProvider) BlackHoles() (holes []FieldLocation) {
+	for _, p := range c.providers {
+		if bhp, ok := p.(BlackHoleProvider); ok {
+			holes = append(holes, bhp.BlackHoles()...)
+		}
+	}
+	return
+}
+
+// Obstacles returns the obstacles of all the providers that have them.
+func (c *CompositeLocationProvider) Obstacles() (walls []FieldLocation) {
+	for _, p := range c.providers {
+		if op, ok := p.(ObstacleProvider); ok {
+			walls = append(walls, op.Obstacles()...)
+		}
+	}
+	return
+}
+
+// MinimumBounds reports the minimum dimensions of a Field that can
+// accommodate the locations of all the providers.
+func (c *CompositeLocationProvider) MinimumBounds() (width, height int) {
+	for _, p := range c.providers {
+		w, h := p.MinimumBounds()
+		width, height = max(width, w), max(height, h)
+	}
+	return
+}
+
+// pathList is a flag.Value that collects the values of a repeated flag.
+type pathList []string
+
+func (p *pathList) String() string {
+	return strings.Join(*p, ",")
+}
+
+func (p *pathList) Set(path string) error {
+	*p = append(*p, path)
+	return nil
+}
+
+// genList is a flag.Value that collects generation numbers given as a
+// comma-separated list, a repeated flag, or both.
+type genList []int
+
+func (g *genList) String() string {
+	nums := make([]string, len(*g))
+	for i, n := range *g {
+		nums[i] = strconv.Itoa(n)
+	}
+	return strings.Join(nums, ",")
+}
+
+func (g *genList) Set(list string) error {
+	for _, item := range strings.Split(list, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(item))
+		if err != nil {
+			return fmt.Errorf("Expected a generation number but got [%v]", item)
+		}
+		*g = append(*g, n)
+	}
+	return nil
+}
+
 // Field represents a two-dimensional field of cells.
 type Field struct {
-	state         [][]bool
+	cells         cellStore
+	blackHoles    overlay
+	obstacles     overlay
 	width, height int
 }
 
 // NewField returns an empty field of the specified width and height.
 func NewField(w, h int) *Field {
-	s := make([][]bool, h)
-	for i := range s {
-		s[i] = make([]bool, w)
+	return &Field{cells: newDenseCells(w, h), width: w, height: h}
+}
+
+// NewSparseField returns an empty field of the specified width and height
+// that only keeps track of its live cells.
+func NewSparseField(w, h int) *Field {
+	return &Field{cells: newSparseCells(w, h), width: w, height: h}
+}
+
+// newField returns an empty field of the specified width and height,
+// sparse if the -sparse option was specified.
+func newField(w, h int) *Field {
+	if sparse {
+		return NewSparseField(w, h)
 	}
-	return &Field{state: s, width: w, height: h}
+	return NewField(w, h)
 }
 
 // set assigns a state to the specified cell.
 func (f *Field) set(loc *FieldLocation, alive bool) {
+	s := dead
+	if alive {
+		s = live
+	}
+	f.setState(loc, s)
+}
+
+// setState assigns a cellState to the specified cell.
+func (f *Field) setState(loc *FieldLocation, s cellState) {
 	if !f.contains(loc) {
 		log.Printf("Out of bounds: %v", loc)
 		return
 	}
-	f.state[loc.Y][loc.X] = alive
+	f.cells.put(loc.X, loc.Y, s)
 }
 
 // contains checks if a Field includes a FieldLocation.
 // Returns true if the give FieldLocation is within the
 // boundaries of the receiving Field
 func (f *Field) contains(loc *FieldLocation) bool {
-	return loc.X < f.width && loc.Y < f.height
+	return loc.X >= 0 && loc.X < f.width && loc.Y >= 0 && loc.Y < f.height
 }
 
 // alive reports whether the specified cell is alive.
 // If the x or y coordinates are outside the field boundaries they are wrapped
 // toroidally. For instance, an x value of -1 is treated as width-1.
 func (f *Field) alive(x, y int) bool {
+	return f.state(x, y).alive()
+}
+
+// state returns the cellState of the specified cell, wrapping the x and y
+// coordinates toroidally the same way alive does. Black holes are dead
+// and obstacles are alive.
+func (f *Field) state(x, y int) cellState {
 	x += f.width
 	x %= f.width
 	y += f.height
 	y %= f.height
-	return f.state[y][x] // && !f.BlackHoled(y, x)
+	if f.blackHoles.has(x, y) {
+		return dead
+	}
+	if f.obstacles.has(x, y) {
+		return live
+	}
+	return f.cells.get(x, y)
+}
+
+// blackHoled reports whether the specified cell is a black hole.
+// The x and y coordinates are wrapped the same way alive does.
+func (f *Field) blackHoled(x, y int) bool {
+	return f.blackHoles.has((x+f.width)%f.width, (y+f.height)%f.height)
+}
+
+// obstructed reports whether the specified cell is an obstacle.
+// The x and y coordinates are wrapped the same way alive does.
+func (f *Field) obstructed(x, y int) bool {
+	return f.obstacles.has((x+f.width)%f.width, (y+f.height)%f.height)
 }
 
 // next returns the state of the specified cell at the next time step.
-func (f *Field) next(x, y int) bool {
-	// Count the adjacent cells that are alive.
-	neighbors := 0
-	for i := -1; i <= 1; i++ {
-		for j := -1; j <= 1; j++ {
-			if (j != 0 || i != 0) && f.alive(x+i, y+j) {
+func (f *Field) next(x, y int, r *rule) cellState {
+	// Count the cells within -range of this one that are alive, and
+	// of those, the ones in the second live state.
+	neighbors, alts := 0, 0
+	for i := -reach; i <= reach; i++ {
+		for j := -reach; j <= reach; j++ {
+			if j == 0 && i == 0 {
+				continue
+			}
+			if s := f.state(x+i, y+j); s.alive() {
 				neighbors++
+				if s == liveAlt {
+					alts++
+				}
 			}
 		}
 	}
-	// Return next state according to the game rules:
-	//   exactly 3 neighbors: on,
-	//   exactly 2 neighbors: maintain current state,
-	//   otherwise: off.
-	return neighbors == 3 || neighbors == 2 && f.alive(x, y)
+	// Black holes never come alive and obstacles never die.
+	if f.blackHoled(x, y) {
+		return dead
+	}
+	if f.obstructed(x, y) {
+		return live
+	}
+	// Dying cells keep aging until they're dead and can't come
+	// alive again before then.
+	s := f.state(x, y)
+	if s >= dying {
+		return s.aged(states)
+	}
+	// Return next state according to the rule, by default B3/S23:
+	//   live with a surviving number of neighbors: stay on,
+	//   live otherwise: off, or start dying if there are dying states,
+	//   dead with a birth number of neighbors: on, in the state of the
+	//   majority of them.
+	switch {
+	case s.alive() && r.survives[neighbors]:
+		return s
+	case s.alive() && states > 2:
+		return dying
+	case s.alive():
+		return dead
+	case r.born[neighbors] && alts*2 > neighbors:
+		return liveAlt
+	case r.born[neighbors]:
+		return live
+	}
+	return dead
+}
+
+// writeState writes the states of all the cells of the Field to w,
+// one byte per cell in row-major order.
+func (f *Field) writeState(w io.Writer) {
+	row := make([]byte, f.width)
+	for y := 0; y < f.height; y++ {
+		for x := range row {
+			row[x] = byte(f.state(x, y))
+		}
+		w.Write(row)
+	}
+}
+
+// ForEach calls fn with the coordinates of every cell of the Field, in
+// row-major order, and whether the cell is alive. It works the same
+// whichever way the cells are stored.
+func (f *Field) ForEach(fn func(x, y int, alive bool)) {
+	for y := 0; y < f.height; y++ {
+		for x := 0; x < f.width; x++ {
+			fn(x, y, f.alive(x, y))
+		}
+	}
+}
+
+// Population returns the number of live cells in the Field.
+func (f *Field) Population() int {
+	n := 0
+	f.cells.eachLive(func(x, y int) {
+		n++
+	})
+	return n
 }
 
 // Life stores the state of a round of Conway's Game of Life.
 type Life struct {
 	thisGen, nextGen        *Field
 	width, height, genCount int
+
+	// accumulates the states of all generations for the -checksum option
+	digest hash.Hash64
+
+	// changes from the previous generation for the -events option
+	changes *Event
+
+	// how often each cell changed for the -activity option
+	activity activityMap
+
+	// hashes of the most recent generations, to detect when they repeat
+	recent []uint64
+
+	// reused by WriteTo to render each generation
+	render bytes.Buffer
+
+	// the component of each live cell for the -components option
+	labels map[FieldLocation]int
+
+	// the rule for the next generation, which can be changed during
+	// an -interactive run
+	rule          rule
+	promptingRule bool
+
+	// the population of each generation, for the summary sparkline,
+	// and the current and largest of them
+	populations                []int
+	population, peakPopulation int
+
+	// how long each generation took to calculate for the -timing option
+	times *generationTimes
+
+	// the recent states of each cell for the -settled option
+	history *cellHistory
+
+	// the hash of the last generation displayed, for the -skip-unchanged
+	// option, or nil if none has been displayed yet
+	shown *uint64
 }
 
-// NewLife returns a new Life game state with initial state provided by Seeder
-func NewLife(w, h int) *Life {
-	firstGen := NewField(w, h)
-	for seeder.moreLocations() {
-		firstGen.set(seeder.nextLocation(), true)
+// NewLife returns a new Life game state with initial state provided by
+// the given Seeder. An error is returned if there is no Seeder or if a
+// field of the given width and height cannot accommodate all the
+// locations it provides.
+func NewLife(w, h int, s *Seeder) (*Life, error) {
+	if s == nil {
+		return nil, fmt.Errorf("No seeder for initial population")
+	}
+	minW, minH := s.provider.MinimumBounds()
+	if w < minW || h < minH {
+		return nil, fmt.Errorf("Field of %vx%v is too small for initial population (need %vx%v)",
+			w, h, minW, minH)
+	}
+	firstGen := newField(w, h)
+	for s.moreLocations() {
+		loc := s.nextLocation()
+		firstGen.setState(loc, initialState(loc, w))
+	}
+	nextGen := newField(w, h)
+	if bhp, ok := s.provider.(BlackHoleProvider); ok {
+		holes := newOverlay(w, h, bhp.BlackHoles())
+		firstGen.blackHoles, nextGen.blackHoles = holes, holes
+	}
+	if op, ok := s.provider.(ObstacleProvider); ok {
+		walls := newOverlay(w, h, op.Obstacles())
+		firstGen.obstacles, nextGen.obstacles = walls, walls
+		for _, loc := range op.Obstacles() {
+			firstGen.setState(&loc, live)
+		}
 	}
-	return &Life{
-		thisGen: firstGen, nextGen: NewField(w, h),
+	random := false
+	switch s.provider.(type) {
+	case *RandomLocationProvider, *SymmetricRandomLocationProvider,
+		*CountedRandomLocationProvider, *ShuffledLocationProvider:
+		random = true
+	}
+	warnIfSparse(firstGen, random)
+	l := &Life{
+		thisGen: firstGen, nextGen: nextGen,
 		width: w, height: h,
+		rule: startRule,
+	}
+	l.countPopulation()
+	l.populations = []int{l.population}
+	return l, nil
+}
+
+// AliveAt reports whether the specified cell of the current generation
+// is alive. Coordinates outside the field are wrapped toroidally.
+func (l *Life) AliveAt(x, y int) bool {
+	return l.thisGen.alive(x, y)
+}
+
+// CellState reports whether the specified cell of the current generation
+// is alive and whether it is within the field at all. Coordinates are not
+// wrapped, so a cell outside the field is never alive.
+func (l *Life) CellState(x, y int) (alive, inBounds bool) {
+	if !l.thisGen.contains(NewFieldLocation(x, y)) {
+		return false, false
 	}
+	return l.thisGen.alive(x, y), true
+}
+
+// Population returns the number of live cells in the current generation.
+func (l *Life) Population() int {
+	return l.population
 }
 
+// PeakPopulation returns the largest number of live cells in any
+// generation so far.
+func (l *Life) PeakPopulation() int {
+	return l.peakPopulation
+}
+
+// countPopulation counts the live cells of the current generation and
+// keeps track of the largest population of any generation so far.
+func (l *Life) countPopulation() {
+	l.population = l.thisGen.Population()
+	l.peakPopulation = max(l.peakPopulation, l.population)
+}
+
+// initialState returns the state of a cell seeded at the given location
+// in a field of the given width. With the -immigration option, cells
+// seeded in the right half of the field are in the second live state.
+func initialState(loc *FieldLocation, w int) cellState {
+	if immigration && loc.X >= w/2 {
+		return liveAlt
+	}
+	return live
+}
+
+// LiveCells returns the locations of all the live cells in the current
+// generation, in row-major order.
+func (l *Life) LiveCells() []FieldLocation {
+	cells := make([]FieldLocation, 0, l.Population())
+	l.thisGen.cells.eachLive(func(x, y int) {
+		cells = append(cells, *NewFieldLocation(x, y))
+	})
+	return cells
+}
+
+// minDensity is the fraction of live cells in the first generation below
+// which a warning is given that the simulation may not be very interesting.
+const minDensity = 0.05
+
+// warnIfSparse logs a warning if the initial population of a Field is
+// empty or, for randomly seeded fields, too sparse to be likely to produce
+// anything worth watching. Patterns loaded from a file are often sparse
+// by design so they are only checked for being empty.
+func warnIfSparse(f *Field, random bool) {
+	pop := f.Population()
+	switch {
+	case pop == 0:
+		log.Println("Warning: initial population is empty; try a different seed or pattern file")
+	case random && float64(pop) < minDensity*float64(f.width*f.height):
+		log.Printf("Warning: initial population of %v is sparse and may die out quickly; "+
+			"try a different seed or a smaller field", pop)
+	}
+}
+
+// prepareNextGeneration calculates the next generation from the cells
+// of the current generation that could possibly be alive in it.
 func (l *Life) prepareNextGeneration() {
-	for y := 0; y < l.height; y++ {
-		for x := 0; x < l.width; x++ {
-			l.nextGen.set(NewFieldLocation(x, y), l.thisGen.next(x, y))
-		}
+	if l.changes != nil {
+		l.changes.Born = []FieldLocation{}
+		l.changes.Died = []FieldLocation{}
 	}
+	l.calculateInto(l.nextGen, func(x, y int, s cellState) {
+		if l.activity != nil && s != l.thisGen.state(x, y) {
+			l.activity.record(x, y)
+		}
+		if l.changes != nil {
+			l.recordChange(x, y, s)
+		}
+	})
+}
+
+// nextInto calculates the successor of the current generation into dst,
+// which must be the same size as the field, leaving the current
+// generation as it is. Unlike step, nothing is recorded for the
+// -checksum, -events, or -activity options.
+func (l *Life) nextInto(dst *Field) {
+	l.calculateInto(dst, nil)
+}
+
+// calculateInto calculates the next generation into dst, calling observe,
+// if it isn't nil, with the next state of every candidate cell.
+func (l *Life) calculateInto(dst *Field, observe func(x, y int, s cellState)) {
+	dst.cells.clear()
+	l.thisGen.cells.eachCandidate(func(x, y int) {
+		s := l.thisGen.next(x, y, &l.rule)
+		if s != dead {
+			dst.cells.put(x, y, s)
+		}
+		if observe != nil {
+			observe(x, y, s)
+		}
+	})
 }
 
 func (l *Life) instateNextGeneration() {
 	l.thisGen, l.nextGen = l.nextGen, l.thisGen
 	l.genCount++
+	l.countPopulation()
+	l.populations = append(l.populations, l.population)
+}
+
+// generation returns the number of the current generation. The first
+// generation is numbered by the -gen0 option.
+func (l *Life) generation() int {
+	return gen0 + l.genCount
 }
 
 // Step advances the game to the next generation
 func (l *Life) step() {
-	l.prepareNextGeneration()
+	if l.times != nil {
+		start := time.Now()
+		l.prepareNextGeneration()
+		*l.times = append(*l.times, time.Since(start))
+	} else {
+		l.prepareNextGeneration()
+	}
 	l.instateNextGeneration()
+	if l.history != nil {
+		l.history.record(l.thisGen)
+	}
 }
 
-// String returns the game board as a string.
-func (l *Life) String() string {
-	const deadcell = "  "
-	var buf bytes.Buffer
-	for y := 0; y < l.height; y++ {
-		for x := 0; x < l.width; x++ {
-			cell := []byte(deadcell)
-			if l.thisGen.alive(x, y) {
-				cell = livecell
+// WriteTo writes the rendering of the current generation to w. The
+// rendering buffer is reused across calls so long runs don't allocate
+// a new one for every generation.
+func (l *Life) WriteTo(w io.Writer) (int64, error) {
+	l.render.Reset()
+	if view == "none" {
+		for y := 0; y < l.height; y++ {
+			for x := 0; x < l.width; x++ {
+				l.render.Write(l.cellIcon(x, y))
 			}
-			buf.Write(cell)
+			l.render.WriteByte('\n')
 		}
-		buf.WriteByte('\n')
+		return l.render.WriteTo(w)
 	}
-	return buf.String()
+
+	// walk the cells in the order they are displayed with the -view
+	// transform, which is undone to find where each one is in the field
+	vw, vh := l.width, l.height
+	if view == "rotate90" || view == "rotate270" {
+		vw, vh = vh, vw
+	}
+	undo := transforms[viewInverses[view]]
+	for vy := 0; vy < vh; vy++ {
+		for vx := 0; vx < vw; vx++ {
+			l.render.Write(l.cellIcon(undo(vx, vy, vw-1, vh-1)))
+		}
+		l.render.WriteByte('\n')
+	}
+	return l.render.WriteTo(w)
 }
 
+// viewInverses are the names of the transforms that undo each of the
+// transforms that can be given with the -view option.
+var viewInverses = map[string]string{
+	"none":      "none",
+	"rotate90":  "rotate270",
+	"rotate180": "rotate180",
+	"rotate270": "rotate90",
+	"flipx":     "flipx",
+	"flipy":     "flipy",
+}
+
+// viewNames returns the names of the transforms for the -view option.
+func viewNames() string {
+	return strings.Join(slices.Sorted(maps.Keys(viewInverses)), ", ")
+}
+
+// cellIcon returns what is displayed for the cell at x, y of the
+// current generation.
+func (l *Life) cellIcon(x, y int) []byte {
+	switch {
+	case l.thisGen.blackHoled(x, y):
+		return holecell
+	case l.thisGen.obstructed(x, y):
+		return wallcell
+	case l.labels[FieldLocation{x, y}] > 0:
+		return componentcells[(l.labels[FieldLocation{x, y}]-1)%len(componentcells)]
+	case l.thisGen.state(x, y) == live:
+		return livecell
+	case l.thisGen.state(x, y) == liveAlt:
+		return altcell
+	case l.thisGen.state(x, y) >= dying:
+		return dyingcells[l.thisGen.state(x, y)-dying]
+	}
+	return deadcell
+}
+
+// String returns the game board as a string.
+func (l *Life) String() string {
+	var sb strings.Builder
+	l.WriteTo(&sb)
+	return sb.String()
+}
+
+// clearScreen is the ANSI escape sequence that clears the terminal and
+// moves the cursor to the top-left corner.
+const clearScreen = "\033[2J\033[H"
+
+// cursorHome is the ANSI escape sequence that moves the cursor to the
+// top-left corner without clearing the terminal.
+const cursorHome = "\033[H"
+
 func (l *Life) showCurrentGeneration(nth int) {
-	fmt.Printf("\n\nGeneration %v (%v of %v):\n%v", l.genCount+1,
-		nth-startGen+1, gens, l)
+	unchanged := false
+	if skipSame {
+		h := l.thisGen.stateHash()
+		unchanged = l.shown != nil && *l.shown == h
+		l.shown = &h
+	}
+	switch {
+	case inplace && unchanged:
+		// only the heading is redrawn over the field that is still shown
+		fmt.Print(cursorHome)
+	case inplace:
+		fmt.Print(clearScreen)
+	default:
+		fmt.Print("\n\n")
+	}
+	if title != "" {
+		fmt.Println(title)
+	}
+	if immortal {
+		// there is no end to count towards
+		fmt.Printf("Generation %v:\n", l.generation())
+	} else {
+		fmt.Printf("Generation %v (%v of %v):\n", l.generation(),
+			nth-startGen+1, gens)
+	}
+	if interactive || l.rule.String() != conway {
+		fmt.Printf("Rule: %v\n", l.rule)
+	}
+	if components {
+		var count int
+		l.labels, count = l.findComponents()
+		fmt.Printf("Components: %v\n", count)
+	}
+	if unchanged {
+		if !inplace {
+			fmt.Println("(unchanged)")
+		}
+		return
+	}
+	l.WriteTo(os.Stdout)
 }
 
 func (l *Life) showRunInfo() {
-	fmt.Printf("%v generations calculated.\n\n", l.genCount)
-	fmt.Printf("To continue: %v -y %v -x %v %v -icon %v -s %v -n %v\n", os.Args[0],
-		l.height, l.width, seedflag, iconName, l.genCount, gens,
+	fmt.Fprintf(summary, "%v generations calculated.\n\n", l.genCount)
+	if len(l.populations) > 1 {
+		lo, hi := minMax(l.populations)
+		fmt.Fprintf(summary, "Population: %v (%v to %v)\n\n", sparkline(l.populations), lo, hi)
+	}
+	gen0flag := ""
+	if gen0 != 1 {
+		gen0flag = " -gen0 " + strconv.Itoa(gen0)
+	}
+	if l.rule.String() != conway {
+		gen0flag += " -rule " + l.rule.String()
+	}
+	if reach != 1 {
+		gen0flag += " -range " + strconv.Itoa(reach)
+	}
+	fmt.Fprintf(summary, "To continue: %v -y %v -x %v %v%v -icon %v -s %v -n %v\n", os.Args[0],
+		l.height, l.width, seedflag, gen0flag, iconName, l.generation()-1, gens,
 	)
 }
 
-func (l *Life) stepThroughAll(gens int) {
-	delay := time.Second / time.Duration(gensPerSec)
+// stepThroughAll steps through the generations, displaying those from
+// startGen onward. It reports how the run ended, which is interrupted if
+// ctx was cancelled before all the generations were stepped through.
+func (l *Life) stepThroughAll(ctx context.Context, gens int) outcome {
+	delay := frameDelay()
+	// generations startGen to maxgen-1 are displayed: gens of them
 	maxgen := gens + startGen
+	capped := maxGens > 0 && maxgen > maxGens
+	if capped {
+		maxgen = maxGens
+	}
+	settled := false
 	for i := 0; i < maxgen; i++ {
+		if ctx.Err() != nil {
+			fmt.Fprintf(summary, "\nInterrupted after generation %v.\n", l.genCount)
+			return interrupted
+		}
 		if startGen <= i {
-			l.showCurrentGeneration(i)
-			time.Sleep(delay)
+			if events {
+				l.emitEvent(i == startGen)
+			} else if !quiet && ((i-startGen)%every == 0 || i == maxgen-1) {
+				l.showCurrentGeneration(i)
+				select {
+				case <-ctx.Done():
+				case <-time.After(delay):
+				}
+				if slices.Contains(pauseAt, l.generation()) {
+					l.waitToResume(ctx)
+				}
+			}
+		} else if progress && !quiet {
+			showFastForwardProgress(i)
+		}
+		select {
+		case line := <-commands:
+			l.handleCommand(line)
+		default:
+		}
+		l.handlePokes()
+		if l.digest != nil {
+			l.thisGen.writeState(l.digest)
 		}
 		l.step()
+		if l.overcrowded() {
+			fmt.Fprintf(summary, "\nPopulation of %v in generation %v exceeded the cap of %v.\n",
+				l.Population(), l.generation(), maxPop)
+			return overcrowded
+		}
+		// only the last few generations matter for whether it settled
+		if i >= maxgen-maxPeriod-1 {
+			settled = l.settled()
+		}
+	}
+	switch {
+	case l.extinct():
+		return wentExtinct
+	case settled:
+		return stabilized
+	case capped:
+		fmt.Fprintf(summary, "\nReached generation cap %v.\n", maxGens)
+		return reachedCap
+	}
+	return completed
+}
+
+// waitToResume waits for a line to be entered, for the -pause-at option,
+// unless ctx is cancelled first. Anything typed on the line is carried
+// out as an -interactive command.
+func (l *Life) waitToResume(ctx context.Context) {
+	fmt.Printf("Paused at generation %v; press Enter to continue", l.generation())
+	select {
+	case <-ctx.Done():
+	case line := <-commands:
+		l.handleCommand(line)
+	}
+}
+
+// frameDelay returns how long to pause after displaying a generation:
+// the -delay option if it was given, otherwise one -r'th of a second.
+func frameDelay() time.Duration {
+	if pause > 0 {
+		return pause
+	}
+	return time.Duration(float64(time.Second) / gensPerSec)
+}
+
+// showFastForwardProgress reports how far along the fast-forward to startGen
+// is. The same line is rewritten using a carriage return and is terminated
+// once the last skipped generation has been calculated.
+func showFastForwardProgress(i int) {
+	fmt.Printf("\rFast-forwarding: %v/%v", i+1, startGen)
+	if i+1 == startGen {
+		fmt.Println()
 	}
 }
 
-// simulate calculates the specified number of generations
-func (l *Life) simulate(gens int) {
-	fmt.Printf("\nConway's Game of Life\n")
-	l.stepThroughAll(gens)
+// simulate calculates the specified number of generations, stopping early
+// if ctx is cancelled. Either way, it finishes by showing how to continue
+// the run. It reports how the run ended.
+func (l *Life) simulate(ctx context.Context, gens int) outcome {
+	if !quiet {
+		fmt.Printf("\nConway's Game of Life\n")
+	}
+	if checksum {
+		l.digest = fnv.New64a()
+	}
+	if events {
+		l.changes = &Event{}
+	}
+	if activity {
+		l.activity = newActivityMap(l.width, l.height)
+	}
+	if timing {
+		l.times = &generationTimes{}
+	}
+	if settle {
+		l.history = newCellHistory(l.width, l.height)
+		l.history.record(l.thisGen)
+	}
+	result := l.stepThroughAll(ctx, gens)
+	if l.digest != nil {
+		fmt.Fprintf(summary, "Checksum: %016x\n", l.digest.Sum64())
+	}
+	if l.activity != nil {
+		l.activity.writeReport(summary)
+	}
+	if l.history != nil {
+		l.history.writeReport(summary, l.thisGen)
+	}
+	if l.times != nil {
+		l.times.writeReport(summary)
+	}
 	l.showRunInfo()
+	return result
 }
 
+// simulateLoop runs the simulation over and over from the same initial
+// population until ctx is cancelled.
+func simulateLoop(ctx context.Context) {
+	for {
+		lastRestart := time.Now()
+		l, err := NewLife(fieldWidth, fieldHeight, seeder)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if l.simulate(ctx, gens) == interrupted || !waitToRestart(ctx, lastRestart) {
+			return
+		}
+		fmt.Printf("\nRestarting from the same initial population...\n")
+		reseed()
+	}
+}
+
+// initStartGen converts the generation to start displaying from into the
+// number of generations to skip. Generations are numbered from -gen0.
 func initStartGen() {
-	if startGen > 1 {
-		fmt.Printf("\nStarting from generation %v...", startGen)
-		startGen--
+	if maxGens < 0 {
+		log.Fatalf("Expected a generation cap of at least 0 but got %v", maxGens)
+	}
+	if maxPop < 0 {
+		log.Fatalf("Expected a population cap of at least 0 but got %v", maxPop)
+	}
+	if maxGens > 0 && startGen-gen0 >= maxGens {
+		log.Fatalf("Cannot start from generation %v with a generation cap of %v", startGen, maxGens)
+	}
+	if gens < 1 && !immortal {
+		log.Printf("Warning: no generations will be displayed with -n %v; "+
+			"-n is how many to display starting from the -s generation", gens)
+	}
+	if startGen > gen0 {
+		if !quiet {
+			fmt.Printf("\nStarting from generation %v...", startGen)
+		}
+		startGen -= gen0
 	} else {
 		startGen = 0
 	}
@@ -287,40 +1122,265 @@ func max(a, b int) int {
 
 // initSeed initializes the Seeder and seed-related vars
 func initSeed() {
+	// -demo option
+	if demoName != "" {
+		flp, d, err := newDemoLocationProvider(demoName)
+		if err != nil {
+			log.Fatal(err)
+		}
+		seeder = NewSeeder(flp)
+		fieldWidth, fieldHeight = d.width, d.height
+		seedflag = "-demo " + demoName
+		return
+	}
+
 	// -f option
-	if initPath != "" {
-		flp, err := NewFileLocationProvider(initPath)
-		if err == nil {
-			minX, minY := flp.MinimumBounds()
-			fieldWidth = max(fieldWidth, minX)
-			fieldHeight = max(fieldHeight, minY)
-			seeder = NewSeeder(flp)
-			seedflag = "-f " + initPath
+	var providers []LocationProvider
+	for _, path := range initPaths {
+		flp, err := NewFileLocationProvider(path)
+		if err != nil {
+			log.Println(err)
+			continue
+		}
+		providers = append(providers, flp)
+		seedflag += " -f " + path
+	}
+	// -bin option
+	if binPath != "" {
+		blp, err := NewBinaryLocationProvider(binPath)
+		if err != nil {
+			log.Println(err)
+		} else {
+			providers = append(providers, blp)
+			seedflag += " -bin " + binPath
+		}
+	}
+
+	// -img option
+	if imgPath != "" {
+		ilp, err := NewImageLocationProvider(imgPath)
+		if err != nil {
+			log.Println(err)
+		} else {
+			providers = append(providers, ilp)
+			seedflag += " -img " + imgPath
 		}
 	}
+	seedflag = strings.TrimSpace(seedflag)
+
+	switch len(providers) {
+	case 0:
+	case 1:
+		seeder = NewSeeder(providers[0])
+	default:
+		seeder = NewSeeder(NewCompositeLocationProvider(providers...))
+	}
+	if seeder != nil {
+		// each dimension is the larger of what was asked for and what
+		// the population needs
+		minX, minY := seeder.provider.MinimumBounds()
+		if fieldWidth < minX && flagGiven("x") {
+			log.Printf("Warning: -x %v is too narrow for the initial population; using -x %v", fieldWidth, minX)
+		}
+		if fieldHeight < minY && flagGiven("y") {
+			log.Printf("Warning: -y %v is too short for the initial population; using -y %v", fieldHeight, minY)
+		}
+		fieldWidth = max(fieldWidth, minX)
+		fieldHeight = max(fieldHeight, minY)
+	}
 
 	// default / fallback
 	if seeder == nil {
 		if seed == 0 {
 			seed = time.Now().UnixNano()
 		}
-		rand.Seed(seed)
-		seeder = NewSeeder(NewRandomLocationProvider(fieldWidth, fieldHeight))
-		seedflag = "-seed " + strconv.FormatInt(seed, 10)
+		rng = rand.New(rand.NewSource(seed))
+		seeder = NewSeeder(newRandomLocationProvider(fieldWidth, fieldHeight))
+		seedflag = randomSeedFlag()
+	}
+}
+
+// randomSeedFlag returns the options that give the same random initial
+// population again.
+func randomSeedFlag() string {
+	flags := "-seed " + strconv.FormatInt(seed, 10)
+	if symmetry != "" {
+		flags += " -symmetry " + symmetry
+	}
+	if cellCount > 0 {
+		flags += " -cells " + strconv.Itoa(cellCount)
+	}
+	if shuffle {
+		flags += " -shuffle"
+	}
+	return flags
+}
+
+// newRandomLocationProvider returns the LocationProvider of random
+// locations for a field of the given dimensions, which are mirrored
+// with the -symmetry option or exactly as many as the -cells option asks for.
+// With the -shuffle option, they are the first of a shuffle of all the
+// locations: as many as -cells asks for or a quarter of the field.
+func newRandomLocationProvider(w, h int) LocationProvider {
+	if shuffle {
+		n := cellCount
+		if n == 0 {
+			n = w * h / 4
+		}
+		slp, err := NewShuffledLocationProvider(w, h, n)
+		if err != nil {
+			log.Fatal(err)
+		}
+		return slp
+	}
+	if cellCount > 0 {
+		clp, err := NewCountedRandomLocationProvider(w, h, cellCount)
+		if err != nil {
+			log.Fatal(err)
+		}
+		return clp
 	}
+	if symmetry == "" {
+		return NewRandomLocationProvider(w, h)
+	}
+	slp, err := NewSymmetricRandomLocationProvider(w, h, symmetry)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return slp
+}
+
+// flagGiven reports whether the named flag was set on the command line
+// or in a -config file.
+func flagGiven(name string) bool {
+	given := false
+	flag.Visit(func(f *flag.Flag) {
+		given = given || f.Name == name
+	})
+	return given
+}
+
+// reseed recreates the Seeder so that it provides the same initial
+// population as it did the first time around.
+func reseed() {
+	seeder = nil
+	seedflag = ""
+	initSeed()
 }
 
-var livecell []byte
+// What is displayed for each kind of cell. Each takes up cellWidth
+// columns, assuming the icons are a single column wide.
+var (
+	livecell, altcell, deadcell []byte
+	holecell, wallcell          []byte
+	cellWidth                   int
+
+	// one for each dying state, fading from light to dark gray
+	dyingcells [][]byte
+
+	// one for each of the componentColors
+	componentcells [][]byte
+)
+
+// fitLines is how many lines of the terminal the -fit option leaves
+// for what is displayed above and below each generation.
+const fitLines = 4
+
+// fitTerminal sizes the field to fill the terminal, for the -fit option.
+// Dimensions given with -x or -y are kept and if stdout isn't a terminal,
+// the field is left as it is.
+func fitTerminal() {
+	cols, rows, ok := terminalSize()
+	if !ok {
+		return
+	}
+	width := 2 // each cell is padded with a leading space unless compact
+	if compact || binary {
+		width = 1
+	}
+	if !flagGiven("x") {
+		fieldWidth = max(1, cols/width)
+	}
+	if !flagGiven("y") {
+		fieldHeight = max(1, rows-fitLines)
+	}
+}
+
+// isTerminal reports whether f is a terminal rather than, say,
+// a file or a pipe.
+func isTerminal(f *os.File) bool {
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
 
 func initDisplay() {
+	// redrawing in place only makes sense on a terminal
+	inplace = inplace && isTerminal(os.Stdout)
+
+	// each cell is padded with a leading space unless compact
+	pad := " "
+	if compact || binary {
+		pad = ""
+	}
+	cellWidth = len(pad) + 1
+
 	s, ok := icon[iconName]
 	if !ok {
-		iconName = "blue-circle" // DEVELOPER: if you edit this, edit usage(), too!
+		iconName = "blue-circle" // DEVELOPER: if you edit this, edit usage() and showIcons(), too!
 		s = icon[iconName]
 	}
-	livecell = []byte(" " + s)
+	livecell = []byte(pad + s)
+
+	s, ok = icon[altIconName]
+	if !ok {
+		altIconName = "no-entry" // DEVELOPER: if you edit this, edit init(), too!
+		s = icon[altIconName]
+	}
+	altcell = []byte(pad + s)
+
+	// without colors, dying cells all look the same and components are
+	// told apart by letter
+	color := useColor(colorMode, isTerminal(os.Stdout), os.Getenv("NO_COLOR"))
+	dyingcells = make([][]byte, states-2)
+	for i := range dyingcells {
+		gray := 252 - i*(252-fadedGray)/max(1, len(dyingcells)-1)
+		dyingcells[i] = fmt.Appendf(nil, "%v\033[38;5;%vm\u25CF\033[0m", pad, gray)
+		if !color {
+			dyingcells[i] = []byte(pad + "\u25CB")
+		}
+	}
+
+	componentcells = make([][]byte, len(componentColors))
+	for i, c := range componentColors {
+		componentcells[i] = fmt.Appendf(nil, "%v\033[38;5;%vm\u25CF\033[0m", pad, c)
+		if !color {
+			componentcells[i] = []byte(pad + string(rune('A'+i)))
+		}
+	}
+
+	deadcell = []byte(pad + " ")
+	holecell = []byte(pad + "\u00B7")
+	wallcell = []byte(pad + "\u2588")
+
+	if binary {
+		livecell, altcell, wallcell = []byte("1"), []byte("1"), []byte("1")
+		deadcell, holecell = []byte("0"), []byte("0")
+		for i := range dyingcells {
+			dyingcells[i] = deadcell
+		}
+		for i := range componentcells {
+			componentcells[i] = livecell
+		}
+	}
 }
 
+// fadedGray is the color of the last dying state, from the grays of
+// the 256-color ANSI palette, which run from 232 (darkest) to 255.
+const fadedGray = 237
+
+// maxStates is the most states a cell can have in Generations.
+const maxStates = int(^cellState(0)) - int(dying) + 2
+
 var icon = map[string]string{
 	"aster-1":      "\u2731",
 	"aster-2":      "\u2749",
@@ -355,21 +1415,103 @@ func init() {
 	flag.Int64Var(&seed, "seed", 0,
 		"seed for initial population (default random)\n\tignored if -f option specified and valid")
 
-	flag.StringVar(&initPath, "f", "", "read initial population from `filename`\n\tif valid, -seed option is ignored")
-	flag.IntVar(&fieldHeight, "y", 30, "height of simulation field")
-	flag.IntVar(&fieldWidth, "x", 30, "width of simulation field")
+	flag.IntVar(&cellCount, "cells", 0, "seed exactly `N` live cells at random instead of about a quarter of the field")
+	flag.BoolVar(&shuffle, "shuffle", false, "seed different random cells, spread evenly, by shuffling all the cells of the field\n\t"+
+		"as many as -cells gives or a quarter of the field")
+	flag.StringVar(&symmetry, "symmetry", "", "mirror the random initial population across the vertical axis (v),\n\t"+
+		"the horizontal axis (h), or both (4)")
+	flag.StringVar(&editPath, "edit", "", "draw the initial population on an -x by -y field and save it to the field definition `file`\n\t"+
+		"starts from the cells already in the file; press r to save and run it")
+	flag.Var(&initPaths, "f", "read initial population from `filename`\n\t"+
+		"repeat to overlay the populations of several files\n\t"+
+		"if valid, -seed option is ignored")
+	flag.StringVar(&binPath, "bin", "", "read initial population from the 1s in a `file` of 0s and 1s as displayed with -binary\n\t"+
+		"combined with any -f populations; if valid, -seed option is ignored")
+	flag.StringVar(&imgPath, "img", "", "read initial population from the dark pixels of a PNG, PBM, or PGM `image`\n\t"+
+		"combined with any -f populations; if valid, -seed option is ignored")
+	flag.StringVar(&demoName, "demo", "", "show off the built-in pattern `name`: "+demoNames()+"\n\t"+
+		"the field size and other seed options are ignored")
+	flag.IntVar(&fieldHeight, "y", 30, "height of simulation field\n\t"+
+		"made taller if the initial population from a file needs it")
+	flag.BoolVar(&fit, "fit", false, "size the field to fill the terminal, unless -x or -y is given\n\t"+
+		"ignored if output is not a terminal")
+	flag.IntVar(&fieldWidth, "x", 30, "width of simulation field\n\t"+
+		"made wider if the initial population from a file needs it")
 	flag.IntVar(&gens, "n", 20, "display up to `N` generations")
-	flag.IntVar(&gensPerSec, "r", 5, "display `N` generations per second")
+	flag.IntVar(&countdown, "countdown", 0, "count down `N` seconds before the run starts, e.g. to get ready to record it")
+	flag.Float64Var(&gensPerSec, "r", 5, "display `N` generations per second, e.g. 0.5 for one every two seconds")
+	flag.DurationVar(&pause, "delay", 0, "pause for `duration` after displaying each generation, e.g. 2s\n\t"+
+		"overrides the -r option")
 	flag.IntVar(&startGen, "s", 0, "start displaying from generation `N`")
+	flag.Var(&pauseAt, "pause-at", "stop after displaying generation `N` until Enter is pressed\n\t"+
+		"give a comma-separated list or repeat to pause at several generations")
+	flag.IntVar(&every, "every", 1, "only display every `N`th generation; the last one is always displayed")
+	flag.IntVar(&gen0, "gen0", 1, "number the initial population as generation `N`\n\t"+
+		"e.g. when it was saved from generation N of an earlier run")
 	flag.StringVar(&iconName, "icon", "", "`name` of icon to use for live cells (default blue-circle)")
+	flag.BoolVar(&progress, "progress", false, "show progress while fast-forwarding to the -s generation")
+	flag.BoolVar(&sparse, "sparse", false, "only keep track of live cells; suits large, mostly empty fields")
+	flag.StringVar(&compare, "compare", "", "run two random simulations side by side using `seedA,seedB`")
+	flag.BoolVar(&inplace, "inplace", false, "redraw each generation in place by clearing the screen\n\t"+
+		"ignored if output is not a terminal")
+	flag.BoolVar(&skipSame, "skip-unchanged", false, "display (unchanged) instead of a generation that is the same as the last one displayed\n\t"+
+		"with -inplace, only the heading is redrawn")
+	flag.StringVar(&colorMode, "color", "auto", "whether to display dying cells and components in color: auto, always, or never\n\t"+
+		"auto uses colors on a terminal unless the NO_COLOR environment variable is set")
+	flag.BoolVar(&binary, "binary", false, "display live cells as 1 and dead cells as 0, without spaces between them")
+	flag.BoolVar(&compact, "compact", false, "display cells without spaces between them, halving the width of the field")
+	flag.StringVar(&view, "view", "none", "display the field rotated or mirrored with the transform `name`: "+viewNames()+"\n\t"+
+		"only the display is changed, not the simulation")
+	flag.StringVar(&title, "title", "", "`text` to display above each generation")
+	flag.BoolVar(&quiet, "quiet", false, "only display the summary at the end of the run")
+	flag.StringVar(&ruleString, "rule", conway, "the `rule` for how many neighbors it takes for cells to be born and survive")
+	flag.IntVar(&reach, "range", 1, "count the live cells up to `R` cells away in any direction as neighbors\n\t"+
+		"e.g. -range 2 -rule B6-9/S5-10; counts over 8 are given as lists and ranges")
+	flag.BoolVar(&interactive, "interactive", false, "read commands from stdin while the generations are displayed:\n\t"+
+		"type r and then a rule like B36/S23 to change the rule from the next generation on")
+	flag.BoolVar(&immigration, "immigration", false,
+		"play the Immigration variant: cells seeded in the right half of the field\n\t"+
+			"are a second kind and newborn cells take after the majority of their parents")
+	flag.StringVar(&configPath, "config", "", "read option settings from a config `file` of name=value lines\n\t"+
+		"options given on the command line take precedence")
+	flag.StringVar(&writePath, "write-config", "", "write the settings of all the options to a config `file` to use with -config")
+	flag.BoolVar(&dumpLocs, "dump-locations", false, "list the locations read from each -f file, sorted by row and column,\n\t"+
+		"and the minimum field size for them, and exit")
+	flag.BoolVar(&listIcons, "list-icons", false, "list the names and glyphs of the available icons, one per line, and exit")
+	flag.IntVar(&states, "states", 2, "play the Generations variant with `N` states: dead, live, and N-2 dying states\n\t"+
+		"dying cells fade away over N-2 generations and don't count as neighbors")
+	flag.StringVar(&altIconName, "alt-icon", "", "`name` of icon to use for the second kind of live cells\n\t"+
+		"with the -immigration option (default no-entry)")
+	flag.BoolVar(&checksum, "checksum", false, "display a checksum of all generations instead of the generations\n\t"+
+		"runs with the same options give the same checksum")
+	flag.BoolVar(&components, "components", false, "count the groups of connected live cells in each generation\n\t"+
+		"and color each group differently")
+	flag.BoolVar(&activity, "activity", false, "at the end of the run, report how many cells ever changed and\n\t"+
+		"show a map of the field where cells that changed more often are darker")
+	flag.BoolVar(&settle, "settled", false, "at the end of the run, show the last generation with the cells that are\n\t"+
+		"still active shaded apart from those that are still or oscillating")
+	flag.BoolVar(&timing, "timing", false, "at the end of the run, report the shortest, average, and longest\n\t"+
+		"time it took to calculate a generation")
+	flag.BoolVar(&events, "events", false, "write the cells born and died in each generation as lines of JSON\n\t"+
+		"instead of the generations; the summary is written to stderr")
+	flag.BoolVar(&immortal, "immortal", false, "run until interrupted, reseeding randomly whenever the population\n\t"+
+		"dies out or settles down; the -n option is ignored")
+	flag.IntVar(&maxGens, "max-gens", 0, "never calculate more than `N` generations from an initial population\n\t"+
+		"with -immortal, reseed when N generations pass without settling down\n\t"+
+		"otherwise, stop early if -s and -n go beyond N (default no cap)")
+	flag.IntVar(&maxPop, "max-pop", 0, "stop when the population grows to more than `N` live cells\n\t"+
+		"with -immortal, reseed instead (default no cap)")
+	flag.StringVar(&serveAddr, "serve", "", "stream the generations as server-sent events of JSON from an HTTP server at `address`\n\t"+
+		"e.g. :8080; the generations are not displayed and the run restarts after -n of them")
+	flag.BoolVar(&loop, "loop", false, "restart from the same initial population until interrupted")
 }
 
 func usage() {
 
-	fmt.Fprintf(os.Stderr, "Usage: %s [-x] [-y] [-r] [-n] [-s] [-f] [-seed] [-icon]\n\n"+
+	fmt.Fprintf(os.Stderr, "Usage: %s [-x] [-y] [-fit] [-r] [-delay] [-countdown] [-n] [-s] [-every] [-pause-at] [-gen0] [-progress] [-quiet] [-checksum] [-activity] [-settled] [-timing] [-components] [-events] [-inplace] [-skip-unchanged] [-loop] [-serve] [-immortal] [-max-gens] [-max-pop] [-sparse] [-immigration] [-rule] [-range] [-interactive] [-states] [-f] [-bin] [-img] [-demo] [-seed] [-cells] [-shuffle] [-symmetry] [-edit] [-compare] [-icon] [-alt-icon] [-list-icons] [-dump-locations] [-config] [-write-config] [-compact] [-binary] [-color] [-view] [-title]\n\n"+
 		"Options:\n\n", os.Args[0])
 	flag.PrintDefaults()
-	fmt.Fprintf(os.Stderr,
+	fmt.Fprint(os.Stderr,
 		"\nAvailable icons for live cells:\n\n"+
 			"Icon\tName\t\tDescription\n"+
 			"----\t--------\t-----------\n"+
@@ -399,18 +1541,224 @@ func usage() {
 			icon["star-8pt"]+"\tstar-8pt\t8-point star\n"+
 			icon["whitedot"]+"\twhitedot\tWhite dot\n",
 	)
+	fmt.Fprintf(os.Stderr,
+		"\nExamples:\n\n"+
+			"  # a random population on the default 30x30 field\n"+
+			"  %[1]v\n\n"+
+			"  # the Acorn methuselah from a field definition file, until it settles down\n"+
+			"  %[1]v -f field-defs/acorn.field -n 5210 -r 50\n\n"+
+			"  # generations 100 to 109 of the random population from seed 42\n"+
+			"  %[1]v -seed 42 -s 100 -n 10\n\n"+
+			"  # snowflakes packed tightly on a wide field\n"+
+			"  %[1]v -icon snowflake -compact -x 80 -y 24\n\n"+
+			"  # keep going, reseeding whenever a population settles down or runs past 500 generations\n"+
+			"  %[1]v -immortal -max-gens 500 -inplace\n",
+		os.Args[0])
+	fmt.Fprintf(os.Stderr,
+		"\nExit status of a run (-compare, -loop, and -immortal runs exit with 0):\n\n"+
+			"%v\tall the generations were calculated\n"+
+			"%v\tthe population went extinct\n"+
+			"%v\tthe population stabilized: it is still or oscillating\n"+
+			"%v\tthe -max-gens cap was reached before all the generations were calculated\n"+
+			"%v\tthe population grew past the -max-pop cap\n"+
+			"%v\tthe run was interrupted\n"+
+			"1\tthe run could not start, e.g. because of invalid options\n",
+		completed, wentExtinct, stabilized, reachedCap, overcrowded, interrupted)
+	if gliderSignal != nil {
+		fmt.Fprintf(os.Stderr,
+			"\nSignals, e.g. sent with kill -USR1 PID while the generations are being calculated:\n\n"+
+				"USR1\tadd a glider heading in a random direction at a random place\n"+
+				"USR2\tclear a random part of the field, up to a quarter of its width and height\n")
+	}
+}
+
+// showIcons writes the name and glyph of each icon, one per line and
+// sorted by name, for scripts that offer a choice of icons.
+func showIcons(w io.Writer) {
+	for _, name := range slices.Sorted(maps.Keys(icon)) {
+		marker := ""
+		if name == "blue-circle" {
+			marker = "\t(default)"
+		}
+		fmt.Fprintf(w, "%v\t%v%v\n", name, icon[name], marker)
+	}
+}
+
+// dumpLocations writes the locations read from each -f file and returns
+// the exit status, which is 1 if there were none or any of them could
+// not be read.
+func dumpLocations(w io.Writer) int {
+	if len(initPaths) == 0 {
+		log.Println("Expected at least one -f file with -dump-locations")
+		return 1
+	}
+	status := 0
+	for _, path := range initPaths {
+		flp, err := NewFileLocationProvider(path)
+		if err != nil {
+			log.Println(err)
+			status = 1
+			continue
+		}
+		flp.writeLocations(w)
+	}
+	return status
 }
 
 // processArgs processes command line arguments
 func processArgs() {
 	flag.Parse()
 
+	if configPath != "" {
+		if err := readConfig(configPath); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if writePath != "" {
+		if err := writeConfig(writePath); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	if listIcons {
+		showIcons(os.Stdout)
+		os.Exit(0)
+	}
+	if dumpLocs {
+		os.Exit(dumpLocations(os.Stdout))
+	}
+	if states < 2 || states > maxStates {
+		log.Fatalf("Expected -states to be from 2 to %v but got %v", maxStates, states)
+	}
+	var err error
+	if startRule, err = parseRule(ruleString); err != nil {
+		log.Fatal(err)
+	}
+	if interactive || len(pauseAt) > 0 {
+		commands = readCommands(os.Stdin)
+	}
+	if gensPerSec <= 0 {
+		log.Fatalf("Expected -r to be more than 0 but got %v", gensPerSec)
+	}
+	if pause < 0 {
+		log.Fatalf("Expected -delay to be at least 0 but got %v", pause)
+	}
+	if reach < 1 || reach > maxRange {
+		log.Fatalf("Expected -range to be from 1 to %v but got %v", maxRange, reach)
+	}
+	if every < 1 {
+		log.Fatalf("Expected -every to be at least 1 but got %v", every)
+	}
+	if !slices.Contains(colorModes, colorMode) {
+		log.Fatalf("Expected -color to be one of %v but got %v", strings.Join(colorModes, ", "), colorMode)
+	}
+	if _, ok := viewInverses[view]; !ok {
+		log.Fatalf("Expected -view to be one of %v but got %v", viewNames(), view)
+	}
+	if cellCount < 0 {
+		log.Fatalf("Expected -cells to be at least 0 but got %v", cellCount)
+	}
+	if (cellCount > 0 || shuffle) && symmetry != "" {
+		log.Fatalf("Cannot use -cells or -shuffle with -symmetry")
+	}
+	if _, ok := symmetries[symmetry]; symmetry != "" && !ok {
+		log.Fatalf("Expected -symmetry to be v, h, or 4 but got %v", symmetry)
+	}
+
+	// a checksum or events replace the display of generations
+	quiet = quiet || checksum || events
+	if events {
+		summary = os.Stderr
+	}
+
+	if fit {
+		fitTerminal()
+	}
+	if editPath != "" {
+		run, err := editPattern(editPath)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if !run {
+			os.Exit(0)
+		}
+		initPaths = pathList{editPath}
+	}
 	initSeed()
+	if min(fieldWidth, fieldHeight) < 2*reach+1 {
+		log.Fatalf("Field of %vx%v is too small for -range %v", fieldWidth, fieldHeight, reach)
+	}
 	initStartGen()
 	initDisplay()
 }
 
+// interruptible returns a context that is cancelled on the first Ctrl-C
+// so that a run can stop gracefully and show how to continue from where
+// it was interrupted. Any further Ctrl-C terminates the program as usual.
+func interruptible() (context.Context, context.CancelFunc) {
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	go func() {
+		<-ctx.Done()
+		cancel()
+	}()
+	return ctx, cancel
+}
+
+// countDown counts down the seconds before a run starts, all on one
+// line, unless ctx is cancelled first. Stdout isn't buffered so each
+// count shows up right away.
+func countDown(ctx context.Context, n int) {
+	for i := n; i > 0 && ctx.Err() == nil; i-- {
+		if i == n {
+			fmt.Print("Starting in ")
+		}
+		fmt.Printf("%v... ", i)
+		select {
+		case <-ctx.Done():
+		case <-time.After(time.Second):
+		}
+	}
+	if n > 0 {
+		fmt.Println()
+	}
+}
+
 func main() {
 	processArgs()
-	NewLife(fieldWidth, fieldHeight).simulate(gens)
+
+	ctx, cancel := interruptible()
+	defer cancel()
+	listenForPokes()
+
+	if !quiet {
+		countDown(ctx, countdown)
+	}
+
+	if compare != "" {
+		seedA, seedB, err := parseCompareSeeds(compare)
+		if err != nil {
+			log.Fatal(err)
+		}
+		simulateCompare(ctx, seedA, seedB)
+		return
+	}
+	if serveAddr != "" {
+		serve(ctx, serveAddr)
+		return
+	}
+	if loop {
+		simulateLoop(ctx)
+		return
+	}
+	if immortal {
+		simulateImmortal(ctx)
+		return
+	}
+	l, err := NewLife(fieldWidth, fieldHeight, seeder)
+	if err != nil {
+		log.Fatal(err)
+	}
+	result := l.simulate(ctx, gens)
+	cancel()
+	os.Exit(int(result))
 }
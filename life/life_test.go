@@ -0,0 +1,435 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"io"
+	"log"
+	"math/rand"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestMain(m *testing.M) {
+	// processArgs sets the rule from the -rule option, and the icons for
+	// the cells, from the options that the tests leave at their defaults.
+	var err error
+	if startRule, err = parseRule(conway); err != nil {
+		panic(err)
+	}
+	initDisplay()
+	os.Exit(m.Run())
+}
+
+// newTestLife returns a Life of the given size seeded from the lines of a
+// field definition.
+func newTestLife(tb testing.TB, w, h int, lines ...string) *Life {
+	tb.Helper()
+	p, err := parseFieldDefinition(tb.Name(), lines)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	l, err := NewLife(w, h, NewSeeder(p))
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return l
+}
+
+// gliderLines is the definition of a glider heading down and to the right.
+var gliderLines = []string{
+	"0: #",
+	"1:  #",
+	"2:###",
+}
+
+func TestGenerationLabels(t *testing.T) {
+	defer func(g0, s int, q bool) { gen0, startGen, quiet = g0, s, q }(gen0, startGen, quiet)
+	quiet = true
+	tests := []struct {
+		gen0, startGen, skipped int
+	}{
+		{1, 1, 0},   // the defaults: start with the initial population
+		{1, 5, 4},   // -s 5
+		{10, 15, 5}, // -gen0 10 -s 15, e.g. to continue a run
+		{10, 3, 0},  // -s before -gen0 starts with the initial population
+		{0, 0, 0},   // -gen0 0 numbers the initial population 0
+	}
+	for _, tt := range tests {
+		gen0, startGen = tt.gen0, tt.startGen
+		initStartGen()
+		if startGen != tt.skipped {
+			t.Errorf("-gen0 %v -s %v skips %v generations, want %v", tt.gen0, tt.startGen, startGen, tt.skipped)
+		}
+		l := newTestLife(t, 5, 5, gliderLines...)
+		for range startGen {
+			l.step()
+		}
+		if want := max(tt.startGen, tt.gen0); l.generation() != want {
+			t.Errorf("-gen0 %v -s %v first displays generation %v, want %v", tt.gen0, tt.startGen, l.generation(), want)
+		}
+	}
+}
+
+func TestAliveAtAndCellState(t *testing.T) {
+	// a single live cell in the bottom-right corner of a 4x3 field
+	l := newTestLife(t, 4, 3, "2:   #")
+	tests := []struct {
+		x, y                     int
+		aliveAt, alive, inBounds bool
+	}{
+		{3, 2, true, true, true},     // the live cell
+		{0, 0, false, false, true},   // a dead cell
+		{-1, -1, true, false, false}, // wraps around to the live cell
+		{-1, 2, true, false, false},
+		{3, -1, true, false, false},
+		{7, 5, true, false, false},
+		{4, 0, false, false, false}, // wraps around to a dead cell
+	}
+	for _, tt := range tests {
+		if got := l.AliveAt(tt.x, tt.y); got != tt.aliveAt {
+			t.Errorf("AliveAt(%v, %v) = %v, want %v", tt.x, tt.y, got, tt.aliveAt)
+		}
+		alive, inBounds := l.CellState(tt.x, tt.y)
+		if alive != tt.alive || inBounds != tt.inBounds {
+			t.Errorf("CellState(%v, %v) = %v, %v, want %v, %v", tt.x, tt.y, alive, inBounds, tt.alive, tt.inBounds)
+		}
+	}
+}
+
+func TestForEachVisitsEveryCellInOrder(t *testing.T) {
+	defer func(s bool) { sparse = s }(sparse)
+	for _, sparse = range []bool{false, true} {
+		l := newTestLife(t, 5, 4, gliderLines...)
+		visits := 0
+		l.thisGen.ForEach(func(x, y int, alive bool) {
+			if x != visits%5 || y != visits/5 {
+				t.Errorf("-sparse=%v: visit %v is cell %v, %v, want %v, %v", sparse, visits, x, y, visits%5, visits/5)
+			}
+			if alive != l.AliveAt(x, y) {
+				t.Errorf("-sparse=%v: cell %v, %v is alive=%v, want %v", sparse, x, y, alive, l.AliveAt(x, y))
+			}
+			visits++
+		})
+		if visits != 5*4 {
+			t.Errorf("-sparse=%v: visited %v cells, want %v", sparse, visits, 5*4)
+		}
+	}
+}
+
+func TestFieldSizeFitsPopulationFromFile(t *testing.T) {
+	defer func(w, h int, p pathList, s *Seeder, f string) {
+		fieldWidth, fieldHeight, initPaths, seeder, seedflag = w, h, p, s, f
+	}(fieldWidth, fieldHeight, initPaths, seeder, seedflag)
+	defer log.SetOutput(os.Stderr)
+
+	// the population needs a 10x6 field
+	path := writeFile(t, "corners.field", "0:#\n5:         #\n")
+	tests := []struct {
+		x, y          string
+		w, h          int
+		narrow, short bool
+	}{
+		{"", "", 10, 6, false, false},      // too small, but not given
+		{"40", "20", 40, 20, false, false}, // big enough as given
+		{"4", "20", 10, 20, true, false},   // too narrow
+		{"40", "3", 40, 6, false, true},    // too short
+		{"4", "3", 10, 6, true, true},      // too small both ways
+	}
+	for _, tt := range tests {
+		restore := withoutTestFlags()
+		fieldWidth, fieldHeight, initPaths, seeder, seedflag = 30, 30, pathList{path}, nil, ""
+		if tt.x != "" {
+			flag.Set("x", tt.x)
+			flag.Set("y", tt.y)
+		} else {
+			fieldWidth, fieldHeight = 5, 5
+		}
+		var warnings bytes.Buffer
+		log.SetOutput(&warnings)
+		initSeed()
+		restore()
+
+		if fieldWidth != tt.w || fieldHeight != tt.h {
+			t.Errorf("-x %q -y %q: field is %vx%v, want %vx%v", tt.x, tt.y, fieldWidth, fieldHeight, tt.w, tt.h)
+		}
+		if got := strings.Contains(warnings.String(), "-x "+tt.x+" is too narrow"); got != tt.narrow {
+			t.Errorf("-x %q -y %q: warned that -x is too narrow: %v, want %v", tt.x, tt.y, got, tt.narrow)
+		}
+		if got := strings.Contains(warnings.String(), "-y "+tt.y+" is too short"); got != tt.short {
+			t.Errorf("-x %q -y %q: warned that -y is too short: %v, want %v", tt.x, tt.y, got, tt.short)
+		}
+	}
+}
+
+func TestViewTransformsTheDisplay(t *testing.T) {
+	defer func(v string, b bool) {
+		view, binary = v, b
+		initDisplay()
+	}(view, binary)
+	binary = true
+	initDisplay()
+
+	// an L-shape that fills a 2x3 field, like the one the transform tests use
+	l := newTestLife(t, 2, 3, "0:#", "1:#", "2:##")
+	tests := []struct {
+		view, want string
+	}{
+		{"none", "10\n10\n11\n"},
+		{"rotate90", "111\n100\n"},
+		{"rotate180", "11\n01\n01\n"},
+		{"rotate270", "001\n111\n"},
+		{"flipx", "01\n01\n11\n"},
+		{"flipy", "11\n10\n10\n"},
+	}
+	for _, tt := range tests {
+		view = tt.view
+		if got := l.String(); got != tt.want {
+			t.Errorf("-view %v displays:\n%vwant:\n%v", tt.view, got, tt.want)
+		}
+	}
+}
+
+func TestRangeCountsNeighborsUpToRCellsAway(t *testing.T) {
+	defer func(r int, s bool) { reach, sparse = r, s }(reach, sparse)
+	// with B1/S, the cells born are the ones with a single live cell
+	// within -range, and the one live cell dies
+	b1, err := parseRule("B1/S")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, sparse = range []bool{false, true} {
+		for reach = 1; reach <= 3; reach++ {
+			l := newTestLife(t, 9, 9, "4:    #")
+			l.rule = b1
+			l.step()
+
+			// the square ring of cells up to -range away from the middle
+			var want []FieldLocation
+			for y := 4 - reach; y <= 4+reach; y++ {
+				for x := 4 - reach; x <= 4+reach; x++ {
+					if x != 4 || y != 4 {
+						want = append(want, FieldLocation{x, y})
+					}
+				}
+			}
+			if got := l.LiveCells(); !slices.Equal(got, want) {
+				t.Errorf("sparse %v -range %v: got live cells %v, want %v", sparse, reach, got, want)
+			}
+		}
+	}
+}
+
+func TestShuffledLocationsAreDistinct(t *testing.T) {
+	defer func(r *rand.Rand) { rng = r }(rng)
+	rng = rand.New(rand.NewSource(42))
+	p, err := NewShuffledLocationProvider(5, 4, 6)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []FieldLocation
+	for p.MoreLocations() {
+		got = append(got, *p.NextLocation())
+	}
+	// the first six of the shuffle of the 20 cells with -seed 42
+	want := []FieldLocation{{2, 2}, {0, 1}, {4, 2}, {4, 1}, {4, 3}, {2, 3}}
+	if !slices.Equal(got, want) {
+		t.Errorf("got locations %v, want %v", got, want)
+	}
+
+	// a shuffle of every cell gives each of them once
+	p, err = NewShuffledLocationProvider(5, 4, 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	seen := map[FieldLocation]bool{}
+	for p.MoreLocations() {
+		seen[*p.NextLocation()] = true
+	}
+	if len(seen) != 20 {
+		t.Errorf("a shuffle of all 20 cells gave %v distinct locations", len(seen))
+	}
+
+	if _, err := NewShuffledLocationProvider(5, 4, 21); err == nil {
+		t.Errorf("placed 21 cells on a 5x4 field, want an error")
+	}
+}
+
+func TestPopulationAndPeakPopulation(t *testing.T) {
+	tests := []struct {
+		name      string
+		lines     []string
+		pop, peak int
+	}{
+		{"glider", gliderLines, 5, 5},
+		{"pre-block", []string{"0:##", "1:#"}, 4, 4}, // becomes a block
+		{"domino", []string{"0:##"}, 0, 2},           // dies out
+	}
+	for _, tt := range tests {
+		l := newTestLife(t, 8, 8, tt.lines...)
+		for range 4 {
+			l.step()
+		}
+		if l.Population() != tt.pop || l.PeakPopulation() != tt.peak {
+			t.Errorf("%v: population %v, peak %v after 4 generations, want %v, %v",
+				tt.name, l.Population(), l.PeakPopulation(), tt.pop, tt.peak)
+		}
+	}
+
+	// killing cells, as clearing part of the field with a poke does, lowers
+	// the population but not the peak
+	l := newTestLife(t, 8, 8, gliderLines...)
+	l.thisGen.setState(NewFieldLocation(1, 0), dead)
+	l.recount()
+	if l.Population() != 4 || l.PeakPopulation() != 5 {
+		t.Errorf("after killing a cell of a glider, population %v, peak %v, want 4, 5",
+			l.Population(), l.PeakPopulation())
+	}
+}
+
+func TestGliderFallsIntoBlackHole(t *testing.T) {
+	hole := []string{"blackhole:6:      ##", "blackhole:7:      ##"}
+	l := newTestLife(t, 12, 12, append(slices.Clone(gliderLines), hole...)...)
+	free := newTestLife(t, 12, 12, gliderLines...)
+	for gen := 1; gen <= 30; gen++ {
+		l.step()
+		free.step()
+		for _, loc := range l.LiveCells() {
+			if l.thisGen.blackHoled(loc.X, loc.Y) {
+				t.Fatalf("generation %v: black hole at %v is alive", gen, loc)
+			}
+		}
+	}
+	if l.Population() != 0 {
+		t.Errorf("population %v after the glider hit the black hole, want 0: %v", l.Population(), l.LiveCells())
+	}
+	if free.Population() != 5 {
+		t.Errorf("population %v of the glider without a black hole, want 5", free.Population())
+	}
+}
+
+func TestBlinkerNextToObstacles(t *testing.T) {
+	wall := []FieldLocation{{5, 2}, {5, 3}, {5, 4}}
+	l := newTestLife(t, 10, 10, "3:  ###", "obstacle:2:     #", "obstacle:3:     #", "obstacle:4:     #")
+	free := newTestLife(t, 10, 10, "3:  ###")
+	start := free.LiveCells()
+	for gen := 1; gen <= 6; gen++ {
+		l.step()
+		free.step()
+		// the wall never dies, however crowded it gets
+		for _, loc := range wall {
+			if !l.AliveAt(loc.X, loc.Y) {
+				t.Fatalf("generation %v: obstacle at %v died", gen, loc)
+			}
+		}
+		if gen == 1 && !l.AliveAt(6, 3) {
+			t.Errorf("the cell on the far side of the wall, whose only live neighbors are the wall, wasn't born")
+		}
+	}
+	if got := free.LiveCells(); !slices.Equal(got, start) {
+		t.Errorf("the free blinker has live cells %v after 6 generations, want %v", got, start)
+	}
+	if got := l.LiveCells(); slices.Equal(got, append(slices.Clone(start), wall...)) {
+		t.Errorf("the blinker next to the wall oscillated like a free one: %v", got)
+	}
+}
+
+// benchmarkRender renders and steps a random population on a 100x100
+// field, generation after generation.
+func benchmarkRender(b *testing.B, render func(l *Life)) {
+	l, err := newRandomLife(100, 100, 1)
+	if err != nil {
+		b.Fatal(err)
+	}
+	for b.Loop() {
+		render(l)
+		l.step()
+	}
+}
+
+func BenchmarkString(b *testing.B) {
+	benchmarkRender(b, func(l *Life) { io.WriteString(io.Discard, l.String()) })
+}
+
+func BenchmarkWriteTo(b *testing.B) {
+	benchmarkRender(b, func(l *Life) { l.WriteTo(io.Discard) })
+}
+
+func TestStillLifesStayStill(t *testing.T) {
+	// the default field, which is larger than the patterns need so that
+	// they don't touch across the edges
+	p, err := NewFileLocationProvider("field-defs/still-lifes.field")
+	if err != nil {
+		t.Fatal(err)
+	}
+	l, err := NewLife(30, 30, NewSeeder(p))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := l.LiveCells()
+	if len(want) != 26 {
+		t.Fatalf("got %v live cells, want the 26 of the five still lifes", len(want))
+	}
+	for gen := 1; gen <= 5; gen++ {
+		l.step()
+		if got := l.LiveCells(); !slices.Equal(got, want) {
+			t.Fatalf("generation %v has live cells %v, want %v", gen, got, want)
+		}
+	}
+}
+
+func TestNextIntoMatchesStep(t *testing.T) {
+	defer func(saved bool) { sparse = saved }(sparse)
+	for _, useSparse := range []bool{false, true} {
+		sparse = useSparse
+		l, err := newRandomLife(40, 30, 7)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for range 10 {
+			before := l.thisGen.stateHash()
+			dst := newField(l.width, l.height)
+			l.nextInto(dst)
+			if l.thisGen.stateHash() != before {
+				t.Fatalf("sparse %v: nextInto changed the current generation", useSparse)
+			}
+			l.step()
+			if dst.stateHash() != l.thisGen.stateHash() {
+				t.Fatalf("sparse %v: nextInto calculated a different generation %v than step",
+					useSparse, l.genCount)
+			}
+		}
+	}
+}
+
+func TestStepThroughAllCalculatesStartGenPlusGens(t *testing.T) {
+	defer func(s, m int, q bool, w io.Writer) {
+		startGen, maxGens, quiet, summary = s, m, q, w
+	}(startGen, maxGens, quiet, summary)
+	quiet, summary = true, io.Discard
+	tests := []struct {
+		gens, skipped, maxGens int
+		calculated             int
+		want                   outcome
+	}{
+		{3, 0, 0, 3, completed},
+		{3, 4, 0, 7, completed},  // -s 5 -n 3 displays generations 5 to 7
+		{0, 4, 0, 4, completed},  // -s 5 -n 0 displays nothing
+		{3, 4, 5, 5, reachedCap}, // -max-gens 5 stops before generation 7
+		{3, 4, 7, 7, completed},
+	}
+	for _, tt := range tests {
+		startGen, maxGens = tt.skipped, tt.maxGens
+		// a glider takes 40 generations to come back to where it
+		// started on a 10x10 field, so it never looks settled
+		l := newTestLife(t, 10, 10, gliderLines...)
+		got := l.stepThroughAll(context.Background(), tt.gens)
+		if l.genCount != tt.calculated || got != tt.want {
+			t.Errorf("-n %v skipping %v with -max-gens %v calculated %v generations and ended with %v, want %v and %v",
+				tt.gens, tt.skipped, tt.maxGens, l.genCount, got, tt.calculated, tt.want)
+		}
+	}
+}
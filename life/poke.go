@@ -0,0 +1,94 @@
+package main
+
+import (
+	"log"
+	"math/rand"
+	"os"
+	"os/signal"
+	"time"
+)
+
+// gliderSignal and clearSignal poke a running simulation: the first adds
+// a glider somewhere on the field and the second clears a part of it.
+// They are SIGUSR1 and SIGUSR2 where those signals exist and nil elsewhere.
+var gliderSignal, clearSignal os.Signal
+
+// pokes receives the signals that poke the simulation.
+var pokes = make(chan os.Signal, 1)
+
+// pokeRng picks where pokes land. It is separate from rng so that pokes
+// don't change the random population of a later -loop or -immortal round.
+var pokeRng = rand.New(rand.NewSource(time.Now().UnixNano()))
+
+// glider is a glider heading down and to the right.
+var glider = []FieldLocation{{X: 1, Y: 0}, {X: 2, Y: 1}, {X: 0, Y: 2}, {X: 1, Y: 2}, {X: 2, Y: 2}}
+
+// listenForPokes starts receiving the signals that poke the simulation.
+func listenForPokes() {
+	if gliderSignal != nil {
+		signal.Notify(pokes, gliderSignal, clearSignal)
+	}
+}
+
+// handlePokes pokes the current generation for each signal received since
+// the last call. It is called between steps so the field is never poked
+// while the next generation is being calculated.
+func (l *Life) handlePokes() {
+	for {
+		select {
+		case sig := <-pokes:
+			if sig == gliderSignal {
+				l.addGlider()
+			} else {
+				l.clearRegion()
+			}
+		default:
+			return
+		}
+	}
+}
+
+// addGlider adds a glider heading in a random direction at a random
+// place, wrapping around the edges of the field.
+func (l *Life) addGlider() {
+	x, y := pokeRng.Intn(l.width), pokeRng.Intn(l.height)
+	flipX, flipY := pokeRng.Intn(2) == 1, pokeRng.Intn(2) == 1
+	for _, c := range glider {
+		if flipX {
+			c.X = 2 - c.X
+		}
+		if flipY {
+			c.Y = 2 - c.Y
+		}
+		loc := NewFieldLocation((x+c.X)%l.width, (y+c.Y)%l.height)
+		if !l.thisGen.blackHoled(loc.X, loc.Y) {
+			l.thisGen.setState(loc, live)
+		}
+	}
+	log.Printf("Poked a glider in at %v", NewFieldLocation(x, y))
+	l.recount()
+}
+
+// clearRegion kills the cells in a random rectangle of up to a quarter of
+// the width and height of the field. Obstacles are left as they are.
+func (l *Life) clearRegion() {
+	w, h := 1+pokeRng.Intn(max(1, l.width/4)), 1+pokeRng.Intn(max(1, l.height/4))
+	x, y := pokeRng.Intn(l.width), pokeRng.Intn(l.height)
+	for j := range h {
+		for i := range w {
+			loc := NewFieldLocation((x+i)%l.width, (y+j)%l.height)
+			if !l.thisGen.obstructed(loc.X, loc.Y) {
+				l.thisGen.setState(loc, dead)
+			}
+		}
+	}
+	log.Printf("Poked a %vx%v hole in at %v", w, h, NewFieldLocation(x, y))
+	l.recount()
+}
+
+// recount updates the population of the current generation after it
+// has been poked.
+func (l *Life) recount() {
+	l.countPopulation()
+	l.populations[len(l.populations)-1] = l.population
+}
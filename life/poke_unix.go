@@ -0,0 +1,9 @@
+//go:build unix
+
+package main
+
+import "syscall"
+
+func init() {
+	gliderSignal, clearSignal = syscall.SIGUSR1, syscall.SIGUSR2
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// RLE returns the live cells of the Field as the body of a run-length
+// encoded pattern, without the header line: b for a dead cell, o for a
+// live cell, $ for the end of a row, each optionally preceded by a count,
+// and ! at the end. Dead cells at the end of a row and empty rows at the
+// bottom of the Field are left out, but empty rows at the top are not, so
+// that ParseRLE puts the cells back where they were.
+func (f *Field) RLE() string {
+	var b strings.Builder
+	run := func(n int, tag byte) {
+		if n > 1 {
+			b.WriteString(strconv.Itoa(n))
+		}
+		b.WriteByte(tag)
+	}
+	tag := func(x, y int) byte {
+		if f.cells.get(x, y).alive() {
+			return 'o'
+		}
+		return 'b'
+	}
+	lastRow := 0
+	for y := 0; y < f.height; y++ {
+		// A row's trailing dead cells are implied by the next $ or !.
+		end := f.width
+		for end > 0 && tag(end-1, y) == 'b' {
+			end--
+		}
+		if end == 0 {
+			continue
+		}
+		if y > lastRow {
+			run(y-lastRow, '$')
+			lastRow = y
+		}
+		for x := 0; x < end; {
+			t, n := tag(x, y), 1
+			for x+n < end && tag(x+n, y) == t {
+				n++
+			}
+			run(n, t)
+			x += n
+		}
+	}
+	b.WriteByte('!')
+	return b.String()
+}
+
+// ParseRLE returns a Field of the specified width and height with the
+// live cells of a run-length encoded pattern body like the ones RLE
+// returns. Whitespace is ignored and so is anything after the !.
+func ParseRLE(body string, w, h int) (*Field, error) {
+	f := newField(w, h)
+	x, y, n := 0, 0, 0
+	for _, c := range body {
+		switch {
+		case c >= '0' && c <= '9':
+			n = n*10 + int(c-'0')
+			continue
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			continue
+		}
+		count := max(n, 1)
+		n = 0
+		switch c {
+		case 'b':
+			x += count
+		case 'o':
+			for range count {
+				loc := NewFieldLocation(x, y)
+				if !f.contains(loc) {
+					return nil, fmt.Errorf("Live cell at %v is outside a field of %vx%v", loc, w, h)
+				}
+				f.set(loc, true)
+				x++
+			}
+		case '$':
+			x, y = 0, y+count
+		case '!':
+			return f, nil
+		default:
+			return nil, fmt.Errorf("Expected b, o, $ or ! in RLE but got [%c]", c)
+		}
+	}
+	return nil, fmt.Errorf("Expected RLE to end with ! but got [%v]", body)
+}
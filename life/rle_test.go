@@ -0,0 +1,52 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestRLERoundTrip(t *testing.T) {
+	tests := []struct {
+		name  string
+		lines []string
+		want  string
+	}{
+		{"glider", gliderLines, "bo$2bo$3o!"},
+		// an empty row at the top, a run of 12, and two $ row breaks at once
+		{"runs", []string{"1:############", "3:#  #"}, "$12o2$o2bo!"},
+	}
+	for _, tt := range tests {
+		l := newTestLife(t, 15, 5, tt.lines...)
+		got := l.thisGen.RLE()
+		if got != tt.want {
+			t.Errorf("%v: RLE() = %q, want %q", tt.name, got, tt.want)
+		}
+		f, err := ParseRLE(got, 15, 5)
+		if err != nil {
+			t.Fatalf("%v: %v", tt.name, err)
+		}
+		parsed := &Life{thisGen: f}
+		if !slices.Equal(parsed.LiveCells(), l.LiveCells()) {
+			t.Errorf("%v: ParseRLE(%q) has live cells %v, want %v", tt.name, got, parsed.LiveCells(), l.LiveCells())
+		}
+	}
+}
+
+func TestRLEOfAnEmptyField(t *testing.T) {
+	if got := newField(15, 5).RLE(); got != "!" {
+		t.Errorf("RLE() of an empty field = %q, want %q", got, "!")
+	}
+}
+
+func TestParseRLERejectsBadPatterns(t *testing.T) {
+	for _, body := range []string{
+		"3o",   // no !
+		"3ox!", // not a tag
+		"16o!", // wider than the field
+		"5$o!", // below the field
+	} {
+		if _, err := ParseRLE(body, 15, 5); err == nil {
+			t.Errorf("ParseRLE(%q) on a 15x5 field succeeded, want an error", body)
+		}
+	}
+}
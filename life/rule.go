@@ -0,0 +1,156 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// maxRange is the largest distance the -range option can count
+// neighbors from, and maxNeighbors is how many neighbors that gives.
+const (
+	maxRange     = 5
+	maxNeighbors = (2*maxRange+1)*(2*maxRange+1) - 1
+)
+
+// rule says how many live neighbors it takes for a dead cell to be born
+// and for a live cell to survive, indexed by the number of neighbors.
+type rule struct {
+	born, survives [maxNeighbors + 1]bool
+}
+
+// conway is the rule of standard Life.
+const conway = "B3/S23"
+
+// parseRule parses a rulestring in B/S notation like "B3/S23", which
+// says that cells are born with 3 neighbors and survive with 2 or 3.
+// Counts of more than 9 neighbors, for a -range of more than 1, are
+// given as a list of counts and ranges of counts like "B34-45/S33-57,60".
+func parseRule(s string) (rule, error) {
+	var r rule
+	b, sv, ok := strings.Cut(strings.ToUpper(strings.TrimSpace(s)), "/")
+	if !ok || !strings.HasPrefix(b, "B") || !strings.HasPrefix(sv, "S") {
+		return r, fmt.Errorf("Expected a rule like %v but got [%v]", conway, s)
+	}
+	for _, part := range []struct {
+		digits string
+		counts *[maxNeighbors + 1]bool
+	}{{b[1:], &r.born}, {sv[1:], &r.survives}} {
+		if strings.ContainsAny(part.digits, ",-") {
+			if err := parseCounts(part.digits, part.counts); err != nil {
+				return r, fmt.Errorf("%v in rule [%v]", err, s)
+			}
+			continue
+		}
+		for _, d := range part.digits {
+			if d < '0' || d > '8' {
+				return r, fmt.Errorf("Expected neighbor counts from 0 to 8 in rule [%v]", s)
+			}
+			part.counts[d-'0'] = true
+		}
+	}
+	return r, nil
+}
+
+// parseCounts marks the neighbor counts in a list like "3,5-7" in counts.
+func parseCounts(list string, counts *[maxNeighbors + 1]bool) error {
+	for _, item := range strings.Split(list, ",") {
+		lo, hi, isRange := strings.Cut(item, "-")
+		if !isRange {
+			hi = lo
+		}
+		from, err1 := strconv.Atoi(lo)
+		to, err2 := strconv.Atoi(hi)
+		if err1 != nil || err2 != nil || from < 0 || to > maxNeighbors || from > to {
+			return fmt.Errorf("Expected neighbor counts from 0 to %v but got [%v]", maxNeighbors, item)
+		}
+		for n := from; n <= to; n++ {
+			counts[n] = true
+		}
+	}
+	return nil
+}
+
+func (r rule) String() string {
+	return "B" + formatCounts(r.born) + "/S" + formatCounts(r.survives)
+}
+
+// formatCounts formats the marked neighbor counts as digits if they are
+// all single digits, otherwise as a list of counts and ranges of counts.
+func formatCounts(counts [maxNeighbors + 1]bool) string {
+	var sb strings.Builder
+	if !slices.Contains(counts[10:], true) {
+		for n, ok := range counts {
+			if ok {
+				fmt.Fprint(&sb, n)
+			}
+		}
+		return sb.String()
+	}
+	for n := 0; n < len(counts); n++ {
+		if !counts[n] {
+			continue
+		}
+		from := n
+		for n+1 < len(counts) && counts[n+1] {
+			n++
+		}
+		if sb.Len() > 0 {
+			sb.WriteByte(',')
+		}
+		if from == n {
+			fmt.Fprint(&sb, n)
+		} else {
+			fmt.Fprintf(&sb, "%v-%v", from, n)
+		}
+	}
+	return sb.String()
+}
+
+// readCommands sends each line read from r on the returned channel, for
+// the -interactive option.
+func readCommands(r io.Reader) <-chan string {
+	lines := make(chan string)
+	go func() {
+		defer close(lines)
+		scanner := bufio.NewScanner(r)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+	}()
+	return lines
+}
+
+// handleCommand carries out a command typed during an interactive run.
+// "r RULE" changes the rule from the next generation on and a bare "r"
+// prompts for the new rule on the next line.
+func (l *Life) handleCommand(line string) {
+	line = strings.TrimSpace(line)
+	if l.promptingRule {
+		l.promptingRule = false
+		l.changeRule(line)
+		return
+	}
+	switch {
+	case line == "r":
+		fmt.Printf("New rule, e.g. %v: ", conway)
+		l.promptingRule = true
+	case strings.HasPrefix(line, "r "):
+		l.changeRule(strings.TrimPrefix(line, "r "))
+	case line != "":
+		fmt.Printf("Unknown command [%v]; type r to change the rule\n", line)
+	}
+}
+
+func (l *Life) changeRule(s string) {
+	r, err := parseRule(s)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	l.rule = r
+	fmt.Printf("Rule changed to %v\n", r)
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Frame is a generation as it is streamed to the clients of the -serve
+// option.
+type Frame struct {
+	Generation int             `json:"generation"`
+	Width      int             `json:"width"`
+	Height     int             `json:"height"`
+	Live       []FieldLocation `json:"live"`
+}
+
+// frameBacklog is how many frames a client can fall behind by before
+// frames are dropped for it rather than holding up the others.
+const frameBacklog = 4
+
+// broadcaster fans each frame out to all the connected clients.
+type broadcaster struct {
+	mu      sync.Mutex
+	clients map[chan []byte]bool
+}
+
+func newBroadcaster() *broadcaster {
+	return &broadcaster{clients: map[chan []byte]bool{}}
+}
+
+// subscribe returns a channel on which a new client receives frames.
+func (b *broadcaster) subscribe() chan []byte {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	c := make(chan []byte, frameBacklog)
+	b.clients[c] = true
+	return c
+}
+
+// unsubscribe stops sending frames to a client that has disconnected.
+func (b *broadcaster) unsubscribe(c chan []byte) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.clients, c)
+}
+
+// publish sends a frame to every client that isn't too far behind.
+func (b *broadcaster) publish(frame []byte) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for c := range b.clients {
+		select {
+		case c <- frame:
+		default:
+		}
+	}
+}
+
+// ServeHTTP streams frames to a client as server-sent events until the
+// client disconnects.
+func (b *broadcaster) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	c := b.subscribe()
+	defer b.unsubscribe(c)
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case frame := <-c:
+			if _, err := fmt.Fprintf(w, "data: %s\n\n", frame); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+// servePage is a minimal page that shows the streamed generations.
+const servePage = `<!DOCTYPE html>
+<title>Conway's Game of Life</title>
+<pre id="field"></pre>
+<script>
+new EventSource("/events").onmessage = e => {
+  const f = JSON.parse(e.data);
+  const rows = Array.from({length: f.height}, () => Array(f.width).fill("·"));
+  for (const c of f.live) rows[c.y][c.x] = "@";
+  document.getElementById("field").textContent =
+    "Generation " + f.generation + "\n" + rows.map(r => r.join(" ")).join("\n");
+};
+</script>
+`
+
+// serve runs the simulation at the -r rate and streams each generation to
+// the clients connected to an HTTP server at addr, until ctx is cancelled.
+// Like the -loop option, it restarts from the same initial population
+// after the -n generations.
+func serve(ctx context.Context, addr string) {
+	b := newBroadcaster()
+	mux := http.NewServeMux()
+	mux.Handle("/events", b)
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, servePage)
+	})
+	server := &http.Server{Addr: addr, Handler: mux}
+	go func() {
+		<-ctx.Done()
+		server.Shutdown(context.Background())
+	}()
+	go streamGenerations(ctx, b)
+
+	log.Printf("Serving generations at http://%v/ (events at /events)", addr)
+	if err := server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		log.Fatal(err)
+	}
+}
+
+// streamGenerations publishes a frame for each generation until ctx is
+// cancelled.
+func streamGenerations(ctx context.Context, b *broadcaster) {
+	ticker := time.NewTicker(frameDelay())
+	defer ticker.Stop()
+	for {
+		l, err := NewLife(fieldWidth, fieldHeight, seeder)
+		if err != nil {
+			log.Fatal(err)
+		}
+		for range max(gens, 1) {
+			frame, err := json.Marshal(Frame{Generation: l.generation(),
+				Width: l.width, Height: l.height, Live: l.LiveCells()})
+			if err != nil {
+				log.Fatal(err)
+			}
+			b.publish(frame)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			l.step()
+		}
+		reseed()
+	}
+}
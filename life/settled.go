@@ -0,0 +1,84 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// historyLength is how many of the most recent generations of each cell
+// are kept to tell whether it has settled: enough to see an oscillation
+// of maxPeriod repeat.
+const historyLength = 2 * maxPeriod
+
+// settledShades show the final generation of a run for the -settled
+// option: dead and live cells that have settled down, then dead and live
+// cells that are still active.
+var settledShades = [2][2]string{{" ", "█"}, {"░", "▓"}}
+
+// cellHistory keeps whether each cell of a Field was alive in each of
+// the most recent generations, one bit per generation with the latest
+// in the lowest bit, for the -settled option.
+type cellHistory struct {
+	bits [][]uint32
+	gens int
+}
+
+func newCellHistory(w, h int) *cellHistory {
+	bits := make([][]uint32, h)
+	for i := range bits {
+		bits[i] = make([]uint32, w)
+	}
+	return &cellHistory{bits: bits}
+}
+
+// record adds the state of every cell of a generation to the history.
+func (c *cellHistory) record(f *Field) {
+	c.gens++
+	f.ForEach(func(x, y int, alive bool) {
+		b := c.bits[y][x] << 1
+		if alive {
+			b |= 1
+		}
+		c.bits[y][x] = b
+	})
+}
+
+// settled reports whether the cell at x, y has been still or repeating
+// with a period of up to maxPeriod over the generations in the history.
+// A cell needs at least two generations of history to have settled and
+// a period can only be seen once it has repeated.
+func (c *cellHistory) settled(x, y int) bool {
+	n := min(c.gens, historyLength)
+	b := c.bits[y][x]
+	for p := 1; p <= min(maxPeriod, n/2); p++ {
+		mask := uint32(1)<<(n-p) - 1
+		if (b^b>>p)&mask == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// writeReport writes how many cells are still active and a map of the
+// final generation where the cells that are still active are shaded.
+func (c *cellHistory) writeReport(w io.Writer, f *Field) {
+	var sb strings.Builder
+	active := 0
+	f.ForEach(func(x, y int, alive bool) {
+		a, l := 0, 0
+		if !c.settled(x, y) {
+			a = 1
+			active++
+		}
+		if alive {
+			l = 1
+		}
+		sb.WriteString(strings.Repeat(settledShades[a][l], cellWidth))
+		if x == f.width-1 {
+			sb.WriteByte('\n')
+		}
+	})
+	fmt.Fprintf(w, "Settled: %v cells settled down, %v still active\n", f.width*f.height-active, active)
+	fmt.Fprintf(w, "%v\n", sb.String())
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// histogramBuckets is the most bars in the histogram of settling times,
+// and histogramWidth is how many characters wide the longest bar is.
+const (
+	histogramBuckets = 10
+	histogramWidth   = 40
+)
+
+// trials tallies how the random populations of an -immortal run ended
+// and the generation at which each one that went extinct or settled
+// down was found to have done so.
+type trials struct {
+	extinct, stabilized, capped, crowded int
+	settleGens                           []int
+}
+
+// record tallies how a population that has just ended ended.
+func (t *trials) record(result outcome, gen int) {
+	switch result {
+	case wentExtinct:
+		t.extinct++
+	case stabilized:
+		t.stabilized++
+	case reachedCap:
+		t.capped++
+		return
+	case overcrowded:
+		t.crowded++
+		return
+	}
+	t.settleGens = append(t.settleGens, gen)
+}
+
+// writeReport writes how the populations ended and a histogram and
+// percentiles of how many generations they took to settle.
+func (t *trials) writeReport(w io.Writer) {
+	fmt.Fprintf(w, "\nPopulations: %v went extinct, %v stabilized, %v reached the generation cap, "+
+		"%v exceeded the population cap\n", t.extinct, t.stabilized, t.capped, t.crowded)
+	if len(t.settleGens) == 0 {
+		return
+	}
+	gens := slices.Sorted(slices.Values(t.settleGens))
+	percentile := func(p int) int {
+		return gens[(len(gens)-1)*p/100]
+	}
+	fmt.Fprintf(w, "Generations to settle: min %v, median %v, 90th percentile %v, max %v\n\n",
+		gens[0], percentile(50), percentile(90), gens[len(gens)-1])
+
+	size := max(1, (gens[len(gens)-1]+histogramBuckets)/histogramBuckets)
+	counts := make([]int, gens[len(gens)-1]/size+1)
+	for _, g := range gens {
+		counts[g/size]++
+	}
+	most := slices.Max(counts)
+	digits := len(fmt.Sprint(gens[len(gens)-1]))
+	for i, n := range counts {
+		bar := strings.Repeat("#", (n*histogramWidth+most-1)/most)
+		fmt.Fprintf(w, "%*v-%-*v | %-*v %v\n", digits, i*size, digits, (i+1)*size-1, histogramWidth, bar, n)
+	}
+	fmt.Fprintln(w)
+}
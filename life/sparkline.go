@@ -0,0 +1,52 @@
+package main
+
+import "slices"
+
+// sparkBlocks are the characters of a sparkline, from lowest to highest.
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// maxSparkWidth is the most characters a sparkline takes up. Longer
+// series are squeezed in by averaging consecutive values.
+const maxSparkWidth = 60
+
+// sparkline renders values as a line of block characters scaled to
+// the smallest and largest of the values.
+func sparkline(values []int) string {
+	values = squeeze(values, maxSparkWidth)
+	lo, hi := minMax(values)
+	spark := make([]rune, len(values))
+	for i, v := range values {
+		level := 0
+		if hi > lo {
+			level = (v - lo) * (len(sparkBlocks) - 1) / (hi - lo)
+		}
+		spark[i] = sparkBlocks[level]
+	}
+	return string(spark)
+}
+
+// squeeze returns values reduced to at most width values, each the
+// average of a run of consecutive values.
+func squeeze(values []int, width int) []int {
+	if len(values) <= width {
+		return values
+	}
+	squeezed := make([]int, width)
+	for i := range squeezed {
+		from, to := i*len(values)/width, (i+1)*len(values)/width
+		sum := 0
+		for _, v := range values[from:to] {
+			sum += v
+		}
+		squeezed[i] = sum / (to - from)
+	}
+	return squeezed
+}
+
+// minMax returns the smallest and largest of values.
+func minMax(values []int) (lo, hi int) {
+	if len(values) == 0 {
+		return 0, 0
+	}
+	return slices.Min(values), slices.Max(values)
+}
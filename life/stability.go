@@ -0,0 +1,150 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"log"
+	"math/rand"
+	"time"
+)
+
+// maxPeriod is the longest period of oscillation that is detected when
+// checking whether a simulation has settled down.
+const maxPeriod = 15
+
+// outcome is how a run of the simulation ended. It is also the exit
+// status of the program.
+type outcome int
+
+const (
+	completed   outcome = 0   // all the generations were calculated
+	wentExtinct outcome = 3   // no live cells are left
+	stabilized  outcome = 4   // the population is still or oscillating
+	reachedCap  outcome = 5   // stopped short by the -max-gens cap
+	overcrowded outcome = 6   // the population grew past the -max-pop cap
+	interrupted outcome = 130 // the conventional status after Ctrl-C
+)
+
+// stateHash returns a hash of the states of all the cells of the Field.
+func (f *Field) stateHash() uint64 {
+	h := fnv.New64a()
+	f.writeState(h)
+	return h.Sum64()
+}
+
+// overcrowded reports whether the population has grown past the -max-pop cap.
+func (l *Life) overcrowded() bool {
+	return maxPop > 0 && l.Population() > maxPop
+}
+
+// extinct reports whether there are no live cells left.
+func (l *Life) extinct() bool {
+	return l.Population() == 0
+}
+
+// settled reports whether the current generation is the same as one of
+// the last maxPeriod generations, meaning that the simulation has become
+// still or is oscillating. Each call records the current generation, so
+// it should be called once per generation.
+func (l *Life) settled() bool {
+	h := l.thisGen.stateHash()
+	repeated := false
+	for _, old := range l.recent {
+		if old == h {
+			repeated = true
+			break
+		}
+	}
+	if len(l.recent) == maxPeriod {
+		l.recent = l.recent[1:]
+	}
+	l.recent = append(l.recent, h)
+	return repeated
+}
+
+// minRestartInterval limits how often simulateImmortal can reseed, and
+// simulateLoop can restart, so that they don't spin when a run is over
+// right away or isn't displayed.
+const minRestartInterval = time.Second
+
+// waitToRestart waits until minRestartInterval has passed since the last
+// restart. It returns false if ctx is cancelled before then.
+func waitToRestart(ctx context.Context, lastRestart time.Time) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case <-time.After(time.Until(lastRestart.Add(minRestartInterval))):
+		return true
+	}
+}
+
+// simulateImmortal runs the simulation indefinitely, until ctx is
+// cancelled. Whenever the population goes extinct or settles down, the
+// field is reseeded with a new random population, as it is when the
+// -max-gens or -max-pop cap is reached. Once interrupted, it reports how long the
+// populations took to settle down.
+func simulateImmortal(ctx context.Context) {
+	l, err := NewLife(fieldWidth, fieldHeight, seeder)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if !quiet {
+		fmt.Printf("\nConway's Game of Life\n")
+	}
+	delay := frameDelay()
+	lastRestart := time.Now()
+	var t trials
+	for ctx.Err() == nil {
+		if !quiet {
+			l.showCurrentGeneration(l.genCount)
+			select {
+			case <-ctx.Done():
+			case <-time.After(delay):
+			}
+		}
+		runaway := maxGens > 0 && l.genCount >= maxGens
+		if runaway {
+			fmt.Printf("\nReached generation cap %v without stabilizing.\n", maxGens)
+		}
+		result := completed
+		switch {
+		case runaway:
+			result = reachedCap
+		case l.overcrowded():
+			fmt.Printf("\nPopulation of %v exceeded the cap of %v.\n", l.Population(), maxPop)
+			result = overcrowded
+		case l.extinct():
+			result = wentExtinct
+		case l.settled():
+			result = stabilized
+		}
+		if result != completed {
+			t.record(result, l.genCount)
+			if !waitToRestart(ctx, lastRestart) {
+				break
+			}
+			lastRestart = time.Now()
+			if l, err = reseedRandomly(); err != nil {
+				log.Fatal(err)
+			}
+			continue
+		}
+		l.handlePokes()
+		l.step()
+	}
+	fmt.Fprintf(summary, "\nInterrupted after generation %v.\n", l.genCount)
+	t.writeReport(summary)
+	l.showRunInfo()
+}
+
+// reseedRandomly returns a new Life with a random population from a new
+// seed, which becomes the seed shown by showRunInfo.
+func reseedRandomly() (*Life, error) {
+	seed = time.Now().UnixNano()
+	fmt.Printf("\nReseeding (seed=%v)\n", seed)
+	rng = rand.New(rand.NewSource(seed))
+	seeder = NewSeeder(newRandomLocationProvider(fieldWidth, fieldHeight))
+	seedflag = randomSeedFlag()
+	return NewLife(fieldWidth, fieldHeight, seeder)
+}
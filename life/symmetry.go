@@ -0,0 +1,87 @@
+package main
+
+import "fmt"
+
+// symmetries are the ways the -symmetry option can mirror a random
+// initial population: across the vertical axis, the horizontal axis,
+// or both, giving 4-fold symmetry.
+var symmetries = map[string]struct{ vertical, horizontal bool }{
+	"v": {vertical: true},
+	"h": {horizontal: true},
+	"4": {vertical: true, horizontal: true},
+}
+
+// SymmetricRandomLocationProvider provides random FieldLocations in one
+// half or one quadrant of a Field, each followed by its mirror images in
+// the rest of the Field.
+type SymmetricRandomLocationProvider struct {
+	i                    int
+	width, height        int
+	vertical, horizontal bool
+	mirrored             []FieldLocation
+}
+
+// NewSymmetricRandomLocationProvider creates a LocationProvider that gives
+// random locations within a Field with the given dimensions that are
+// symmetric in the named way, which must be one of the symmetries. Like
+// a RandomLocationProvider, the locations provided will cover roughly a
+// quarter of the entire area of the Field.
+func NewSymmetricRandomLocationProvider(w, h int, symmetry string) (*SymmetricRandomLocationProvider, error) {
+	s, ok := symmetries[symmetry]
+	if !ok {
+		return nil, fmt.Errorf("Unknown symmetry [%v]; expected v, h, or 4", symmetry)
+	}
+	return &SymmetricRandomLocationProvider{width: w, height: h,
+		vertical: s.vertical, horizontal: s.horizontal}, nil
+}
+
+// NextLocation gives the next random location or the next mirror image
+// of the last one. There is no guarantee that the locations provided
+// will be unique.
+func (r *SymmetricRandomLocationProvider) NextLocation() (loc *FieldLocation) {
+	if len(r.mirrored) == 0 {
+		r.i++
+		r.mirrored = r.mirror(rng.Intn(r.half(r.width, r.vertical)), rng.Intn(r.half(r.height, r.horizontal)))
+	}
+	loc = &r.mirrored[0]
+	r.mirrored = r.mirrored[1:]
+	return
+}
+
+// half returns how much of a dimension of size n random locations are
+// picked from, which is half of it, rounded up, if it is mirrored.
+func (r SymmetricRandomLocationProvider) half(n int, mirrored bool) int {
+	if mirrored {
+		return (n + 1) / 2
+	}
+	return n
+}
+
+// mirror returns the location x, y along with its mirror images.
+func (r SymmetricRandomLocationProvider) mirror(x, y int) []FieldLocation {
+	locs := []FieldLocation{*NewFieldLocation(x, y)}
+	if r.vertical {
+		locs = append(locs, *NewFieldLocation(r.width-1-x, y))
+	}
+	if r.horizontal {
+		for _, l := range locs {
+			locs = append(locs, *NewFieldLocation(l.X, r.height-1-y))
+		}
+	}
+	return locs
+}
+
+// MoreLocations reports whether a SymmetricRandomLocationProvider has more
+// locations to give, counting the mirror images of each random location
+// towards roughly a quarter of the entire area of the Field.
+func (r SymmetricRandomLocationProvider) MoreLocations() bool {
+	copies := len(r.mirror(0, 0))
+	return len(r.mirrored) > 0 || r.i*copies < r.width*r.height/4
+}
+
+// MinimumBounds reports the minimum dimensions of a Field so that it can
+// accommodate any location that can be given by a
+// SymmetricRandomLocationProvider, which is the whole Field it mirrors.
+func (r SymmetricRandomLocationProvider) MinimumBounds() (width, height int) {
+	return r.width, r.height
+}
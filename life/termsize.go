@@ -0,0 +1,26 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+)
+
+// terminalSize returns the number of columns and rows of the terminal
+// that stdout is, or ok false if it isn't a terminal or its size can't be
+// found out. The size is asked of stty, which reads it from stdin.
+func terminalSize() (cols, rows int, ok bool) {
+	if !isTerminal(os.Stdout) {
+		return 0, 0, false
+	}
+	cmd := exec.Command("stty", "size")
+	cmd.Stdin = os.Stdin
+	out, err := cmd.Output()
+	if err != nil {
+		return 0, 0, false
+	}
+	if _, err := fmt.Sscan(string(out), &rows, &cols); err != nil || rows < 1 || cols < 1 {
+		return 0, 0, false
+	}
+	return cols, rows, true
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"time"
+)
+
+// generationTimes are how long it took to calculate each generation of a
+// run, for the -timing option.
+type generationTimes []time.Duration
+
+// writeReport writes the shortest, average, and longest time it took to
+// calculate a generation.
+func (t generationTimes) writeReport(w io.Writer) {
+	if len(t) == 0 {
+		return
+	}
+	var total time.Duration
+	for _, d := range t {
+		total += d
+	}
+	avg := total / time.Duration(len(t))
+	fmt.Fprintf(w, "Time per generation: %v min, %v avg, %v max over %v generations\n\n",
+		slices.Min(t), avg, slices.Max(t), len(t))
+}
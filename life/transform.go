@@ -0,0 +1,60 @@
+package main
+
+import "log"
+
+// transform maps a location (dx, dy), relative to the top-left corner of
+// a bounding box w+1 cells wide and h+1 cells high, to its new location
+// relative to the top-left corner of the transformed bounding box.
+type transform func(dx, dy, w, h int) (x, y int)
+
+// transforms are the transforms that can be applied to a block of
+// locations with the "transform:" directive in a field definition file.
+// Rotations are clockwise.
+var transforms = map[string]transform{
+	"none":      func(dx, dy, w, h int) (int, int) { return dx, dy },
+	"rotate90":  func(dx, dy, w, h int) (int, int) { return h - dy, dx },
+	"rotate180": func(dx, dy, w, h int) (int, int) { return w - dx, h - dy },
+	"rotate270": func(dx, dy, w, h int) (int, int) { return dy, w - dx },
+	"flipx":     func(dx, dy, w, h int) (int, int) { return w - dx, dy },
+	"flipy":     func(dx, dy, w, h int) (int, int) { return dx, h - dy },
+}
+
+// lookupTransform returns the named transform. The identity transform
+// is returned if there is no transform with the given name.
+func lookupTransform(name string) transform {
+	t, ok := transforms[name]
+	if !ok {
+		log.Printf("Unknown transform [%v]", name)
+		return transforms["none"]
+	}
+	return t
+}
+
+// bounds returns the top-left and bottom-right corners of the smallest
+// box that contains all the given locations.
+func bounds(locs []FieldLocation) (minX, minY, maxX, maxY int) {
+	if len(locs) == 0 {
+		return
+	}
+	minX, minY = locs[0].X, locs[0].Y
+	maxX, maxY = minX, minY
+	for _, l := range locs {
+		minX, maxX = min(minX, l.X), max(maxX, l.X)
+		minY, maxY = min(minY, l.Y), max(maxY, l.Y)
+	}
+	return
+}
+
+// apply transforms a block of locations within its bounding box. The
+// top-left corner of the transformed block is at the same location as
+// that of the original block.
+func (t transform) apply(locs []FieldLocation) []FieldLocation {
+	minX, minY, maxX, maxY := bounds(locs)
+	w, h := maxX-minX, maxY-minY
+	moved := make([]FieldLocation, len(locs))
+	for i, l := range locs {
+		x, y := t(l.X-minX, l.Y-minY, w, h)
+		moved[i] = *NewFieldLocation(x+minX, y+minY)
+	}
+	return moved
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// picture draws the locations, relative to the top-left corner of their
+// bounding box, as rows of # and . like in a field definition file.
+func picture(locs []FieldLocation) string {
+	minX, minY, maxX, maxY := bounds(locs)
+	rows := make([][]byte, maxY-minY+1)
+	for y := range rows {
+		rows[y] = []byte(strings.Repeat(".", maxX-minX+1))
+	}
+	for _, l := range locs {
+		rows[l.Y-minY][l.X-minX] = '#'
+	}
+	lines := make([]string, len(rows))
+	for y, row := range rows {
+		lines[y] = string(row)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// lShape is an asymmetric pattern, so each transform gives a different
+// picture of it.
+var lShape = []FieldLocation{{5, 3}, {5, 4}, {5, 5}, {6, 5}}
+
+func TestTransforms(t *testing.T) {
+	tests := []struct {
+		name, want string
+	}{
+		{"none", "#.\n#.\n##"},
+		{"rotate90", "###\n#.."},
+		{"rotate180", "##\n.#\n.#"},
+		{"rotate270", "..#\n###"},
+		{"flipx", ".#\n.#\n##"},
+		{"flipy", "##\n#.\n#."},
+	}
+	for _, tt := range tests {
+		moved := transforms[tt.name].apply(lShape)
+		if got := picture(moved); got != tt.want {
+			t.Errorf("%v of the L-shape:\n%v\nwant:\n%v", tt.name, got, tt.want)
+		}
+		if minX, minY, _, _ := bounds(moved); minX != 5 || minY != 3 {
+			t.Errorf("%v moved the top-left corner to (%v, %v), want (5, 3)", tt.name, minX, minY)
+		}
+	}
+}
+
+func TestFourQuarterTurnsAreNone(t *testing.T) {
+	locs := lShape
+	for range 4 {
+		locs = transforms["rotate90"].apply(locs)
+	}
+	if got, want := picture(locs), picture(lShape); got != want {
+		t.Errorf("four rotate90s of the L-shape:\n%v\nwant:\n%v", got, want)
+	}
+}
+
+func TestTransformDirective(t *testing.T) {
+	lines := []string{
+		"transform:rotate90",
+		"3:    #",
+		"++:    #",
+		"++:    ##",
+	}
+	f, err := parseFieldDefinition("rotated L", lines)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := picture(f.locs), "###\n#.."; got != want {
+		t.Errorf("rotated L-shape:\n%v\nwant:\n%v", got, want)
+	}
+	if minX, minY, _, _ := bounds(f.locs); minX != 4 || minY != 3 {
+		t.Errorf("rotated L-shape starts at (%v, %v), want (4, 3)", minX, minY)
+	}
+}
@@ -0,0 +1,350 @@
+// Package engine has the rules of Rock-Paper-Scissors-Lizard-Spock: the
+// moves, which of them beats which, and the sentences that describe the
+// result of a matchup. The command line demo in rps.go uses it.
+package engine
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"text/template"
+)
+
+// Move is one of the five moves a player can make.
+type Move int
+
+const (
+	ROCK Move = iota
+	SPOCK
+	PAPER
+	LIZARD
+	SCISSORS
+	LAST_Move
+)
+
+var moveNames = []string{
+	"Rock",
+	"Spock",
+	"Paper",
+	"Lizard",
+	"Scissors",
+}
+
+// MoveNames returns the names of the moves, in the order of the moves.
+func MoveNames() []string {
+	return slices.Clone(moveNames)
+}
+
+// MatchUp is a pairing of a move that wins and the move it beats, with
+// the verbs that describe how it wins.
+type MatchUp struct {
+	p1, p2 Move
+	w, l   string
+}
+
+/*
+http://bigbangtheory.wikia.com/wiki/Rock_Paper_Scissors_Lizard_Spock
+
+Scissors cut Paper
+Paper covers Rock
+Rock crushes Lizard
+Lizard poisons Spock
+Spock smashes Scissors
+Scissors decapitates Lizard
+Lizard eats Paper
+Paper disproves Spock
+Spock vaporizes Rock
+(and as it always has) Rock crushes Scissors
+
+*/
+
+var pairings = []*MatchUp{
+	&MatchUp{SCISSORS, PAPER, "cuts", "cut"},
+	&MatchUp{PAPER, ROCK, "covers", "covered"},
+	&MatchUp{ROCK, LIZARD, "crushes", "crushed"},
+	&MatchUp{LIZARD, SPOCK, "poisons", "poisoned"},
+	&MatchUp{SPOCK, SCISSORS, "smashes", "smashed"},
+	&MatchUp{SCISSORS, LIZARD, "decapitates", "decapitated"},
+	&MatchUp{LIZARD, PAPER, "eats", "eaten"},
+	&MatchUp{PAPER, SPOCK, "disproves", "disproved"},
+	&MatchUp{SPOCK, ROCK, "vaporizes", "vaporized"},
+	&MatchUp{ROCK, SCISSORS, "crushes", "crushed"},
+}
+
+// Pairings returns a copy of the matchups that Versus describes results
+// with. Use SetPairings to change them.
+func Pairings() []*MatchUp {
+	return slices.Clone(pairings)
+}
+
+// SetPairings replaces the matchups that Versus describes results with,
+// e.g. with ones from LoadPairings.
+func SetPairings(ps []*MatchUp) {
+	pairings = ps
+}
+
+// LoadPairings reads matchups from a file with one matchup per line in
+// the form "winner loser winverb loseverb", e.g. "Paper Rock covers covered".
+// Blank lines and lines that start with "#" are ignored.
+func LoadPairings(path string) ([]*MatchUp, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var ps []*MatchUp
+	scanner := bufio.NewScanner(file)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		m, err := parseMatchUp(line)
+		if err != nil {
+			return nil, fmt.Errorf("%v line %v: %v", path, n, err)
+		}
+		ps = append(ps, m)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return ps, validatePairings(ps)
+}
+
+func parseMatchUp(line string) (*MatchUp, error) {
+	fields := strings.Fields(line)
+	if len(fields) != 4 {
+		return nil, fmt.Errorf("Expected winner, loser, winning verb, and losing verb: %v", line)
+	}
+	winner, err := ParseMove(fields[0])
+	if err != nil {
+		return nil, err
+	}
+	loser, err := ParseMove(fields[1])
+	if err != nil {
+		return nil, err
+	}
+	if !winner.Beats(loser) {
+		return nil, fmt.Errorf("%v does not beat %v", winner, loser)
+	}
+	return &MatchUp{winner, loser, fields[2], fields[3]}, nil
+}
+
+// validatePairings checks that there is exactly one matchup for every
+// pair of moves where one beats the other.
+func validatePairings(ps []*MatchUp) error {
+	for p1 := Move(0); p1.NotLast(); p1++ {
+		for p2 := Move(0); p2.NotLast(); p2++ {
+			if !p1.Beats(p2) {
+				continue
+			}
+			found := 0
+			for _, m := range ps {
+				if m.p1 == p1 && m.p2 == p2 {
+					found++
+				}
+			}
+			if found != 1 {
+				return fmt.Errorf("Expected 1 pairing for %v vs %v but found %v", p1, p2, found)
+			}
+		}
+	}
+	return nil
+}
+
+// Sentences are the text/template strings that describe the result of a
+// matchup. Each is executed with a Sentence for the matchup.
+type Sentences struct {
+	Win, Lose, Tie string
+}
+
+// Sentence is what a template of Sentences can describe a matchup with.
+// ToBe is "is" or "are" to agree with the Loser, e.g. "Scissors are".
+type Sentence struct {
+	Winner, Loser     Move
+	WinVerb, LoseVerb string
+	ToBe              string
+}
+
+// DefaultSentences describe the matchups the way Sheldon does.
+var DefaultSentences = Sentences{
+	Win:  "{{.Winner}} {{.WinVerb}} {{.Loser}}",
+	Lose: "{{.Loser}} {{.ToBe}} {{.LoseVerb}} by {{.Winner}}",
+	Tie:  "{{.Winner}} ties {{.Loser}}",
+}
+
+var winTemplate, loseTemplate, tieTemplate *template.Template
+
+// init sets up the templates for the default sentences, which Versus
+// uses unless SetSentences is given others.
+func init() {
+	if err := SetSentences(DefaultSentences); err != nil {
+		panic(err)
+	}
+}
+
+// SetSentences replaces the templates used to describe the results of
+// matchups. Each template is tried out on a matchup so that one that
+// can't be executed is reported here rather than when it is used.
+func SetSentences(s Sentences) error {
+	sample := (&MatchUp{SCISSORS, PAPER, "cuts", "cut"}).sentence()
+	parse := func(name, text string) (t *template.Template, err error) {
+		t, err = template.New(name).Parse(text)
+		if err == nil {
+			err = t.Execute(io.Discard, sample)
+		}
+		if err != nil {
+			err = fmt.Errorf("Invalid %v sentence [%v]: %v", name, text, err)
+		}
+		return
+	}
+	win, err := parse("win", s.Win)
+	if err != nil {
+		return err
+	}
+	lose, err := parse("lose", s.Lose)
+	if err != nil {
+		return err
+	}
+	tie, err := parse("tie", s.Tie)
+	if err != nil {
+		return err
+	}
+	winTemplate, loseTemplate, tieTemplate = win, lose, tie
+	return nil
+}
+
+// LoadSentences reads the templates for the results of matchups from a
+// file with lines like "win: {{.Winner}} {{.WinVerb}} {{.Loser}}". The
+// sentences it leaves out are the default ones. Blank lines and lines
+// that start with "#" are ignored.
+func LoadSentences(path string) (Sentences, error) {
+	s := DefaultSentences
+	file, err := os.Open(path)
+	if err != nil {
+		return s, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, text, _ := strings.Cut(line, ":")
+		text = strings.TrimSpace(text)
+		switch strings.TrimSpace(key) {
+		case "win":
+			s.Win = text
+		case "lose":
+			s.Lose = text
+		case "tie":
+			s.Tie = text
+		default:
+			return s, fmt.Errorf("%v line %v: Expected win, lose, or tie but got [%v]", path, n, key)
+		}
+	}
+	return s, scanner.Err()
+}
+
+// execute returns the text of t for s. The templates are tried out by
+// SetSentences, so they are not expected to fail here.
+func execute(t *template.Template, s Sentence) string {
+	var b strings.Builder
+	if err := t.Execute(&b, s); err != nil {
+		panic(err)
+	}
+	return b.String()
+}
+
+// sentence returns what the templates can describe m with.
+func (m *MatchUp) sentence() Sentence {
+	return Sentence{Winner: m.p1, Loser: m.p2, WinVerb: m.w, LoseVerb: m.l, ToBe: toBe(m.p2)}
+}
+
+// toBe returns the form of "to be" that agrees with m.
+func toBe(m Move) string {
+	if m == SCISSORS {
+		return "are"
+	}
+	return "is"
+}
+
+// WinResult describes the winner of m beating the loser.
+func (m *MatchUp) WinResult() string {
+	return execute(winTemplate, m.sentence())
+}
+
+// LoseResult describes the loser of m being beaten by the winner.
+func (m *MatchUp) LoseResult() string {
+	return execute(loseTemplate, m.sentence())
+}
+
+func (m Move) String() string {
+	if m.InRange() {
+		return moveNames[m]
+	}
+	return ""
+}
+
+// ParseMove returns the Move with the given name, ignoring case.
+func ParseMove(s string) (Move, error) {
+	for m := Move(0); m.NotLast(); m++ {
+		if strings.EqualFold(s, m.String()) {
+			return m, nil
+		}
+	}
+	return LAST_Move, fmt.Errorf("Unknown move: %v", s)
+}
+
+// NotLast reports whether m comes before LAST_Move, for looping over
+// the moves.
+func (m Move) NotLast() bool {
+	return m < LAST_Move
+}
+
+// InRange reports whether m is one of the moves.
+func (m Move) InRange() bool {
+	return m >= 0 && m.NotLast()
+}
+
+// TieResult describes a matchup of a move against itself.
+func TieResult(m Move) string {
+	return execute(tieTemplate, Sentence{Winner: m, Loser: m, ToBe: toBe(m)})
+}
+
+// Versus returns the result of m1 against m2. It returns an error if
+// there is no pairing for the two moves.
+func (m1 Move) Versus(m2 Move) (string, error) {
+	if m1 == m2 {
+		return TieResult(m1), nil
+	}
+	matchUp, err := findMatchUp(m1, m2)
+	if err != nil {
+		return "", err
+	}
+	if m1.Beats(m2) {
+		return matchUp.WinResult(), nil
+	}
+	return matchUp.LoseResult(), nil
+}
+
+// Beats reports whether m1 wins against m2.
+func (m1 Move) Beats(m2 Move) bool {
+	return m1 != m2 && (m1-m2+LAST_Move)%LAST_Move <= 2
+}
+
+func findMatchUp(p1, p2 Move) (*MatchUp, error) {
+	for _, m := range pairings {
+		if m.p1 == p1 && m.p2 == p2 || m.p1 == p2 && m.p2 == p1 {
+			return m, nil
+		}
+	}
+	return nil, errors.New(fmt.Sprintf("No pairing found for %v vs %v", p1, p2))
+}
@@ -0,0 +1,152 @@
+package engine
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeFile writes the contents to a file with the given name in a
+// temporary directory and returns its path.
+func writeFile(t *testing.T, name, contents string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// TestMoves checks that moveNames and the built-in pairings are in sync
+// with the moves, e.g. after a move is added.
+func TestMoves(t *testing.T) {
+	if len(moveNames) != int(LAST_Move) {
+		t.Errorf("%v move names for %v moves", len(moveNames), int(LAST_Move))
+	}
+	for m := Move(0); m.NotLast(); m++ {
+		if p, err := ParseMove(m.String()); p != m {
+			t.Errorf("Move %d does not round trip: ParseMove(%q) = %v, %v", m, m.String(), p, err)
+		}
+	}
+	if LAST_Move.String() != "" {
+		t.Errorf("LAST_Move has the name %q", LAST_Move.String())
+	}
+	if err := validatePairings(pairings); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestTies(t *testing.T) {
+	want := []string{"Rock ties Rock", "Spock ties Spock", "Paper ties Paper",
+		"Lizard ties Lizard", "Scissors ties Scissors"}
+	for m := Move(0); m.NotLast(); m++ {
+		if got, err := m.Versus(m); got != want[m] || err != nil {
+			t.Errorf("%v.Versus(%v) = %q, %v, want %q", m, m, got, err, want[m])
+		}
+	}
+}
+
+func TestCustomSentences(t *testing.T) {
+	defer SetSentences(DefaultSentences)
+	path := writeFile(t, "sentences", "# the lose and tie sentences are left as they are\n"+
+		"win: {{.Winner}} {{.WinVerb}} {{.Loser}}!!!\n")
+	s, err := LoadSentences(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := SetSentences(s); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		m1, m2 Move
+		want   string
+	}{
+		{SCISSORS, PAPER, "Scissors cuts Paper!!!"},
+		{PAPER, SCISSORS, "Paper is cut by Scissors"},
+		{ROCK, ROCK, "Rock ties Rock"},
+	}
+	for _, tt := range tests {
+		if got, err := tt.m1.Versus(tt.m2); got != tt.want || err != nil {
+			t.Errorf("%v.Versus(%v) = %q, %v, want %q", tt.m1, tt.m2, got, err, tt.want)
+		}
+	}
+}
+
+func TestSetSentencesRejectsBadTemplates(t *testing.T) {
+	defer SetSentences(DefaultSentences)
+	for _, s := range []Sentences{
+		{Win: "{{.Winner", Lose: DefaultSentences.Lose, Tie: DefaultSentences.Tie}, // doesn't parse
+		{Win: DefaultSentences.Win, Lose: "{{.Score}}", Tie: DefaultSentences.Tie}, // no such field
+	} {
+		if err := SetSentences(s); err == nil {
+			t.Errorf("SetSentences(%+v) succeeded, want an error", s)
+		}
+	}
+	if got, _ := SCISSORS.Versus(PAPER); got != "Scissors cuts Paper" {
+		t.Errorf("after rejected sentences, Versus gives %q, want the default sentence", got)
+	}
+}
+
+// customPairings are the built-in pairings with other verbs.
+const customPairings = `# winner loser winverb loseverb
+Scissors Paper snips snipped
+Paper Rock wraps wrapped
+Rock Lizard squashes squashed
+Lizard Spock bites bitten
+Spock Scissors bends bent
+
+Scissors Lizard slices sliced
+Lizard Paper chews chewed
+Paper Spock refutes refuted
+Spock Rock zaps zapped
+Rock Scissors blunts blunted
+`
+
+func TestLoadPairings(t *testing.T) {
+	defer SetPairings(Pairings())
+	ps, err := LoadPairings(writeFile(t, "pairings", customPairings))
+	if err != nil {
+		t.Fatal(err)
+	}
+	SetPairings(ps)
+	tests := []struct {
+		m1, m2 Move
+		want   string
+	}{
+		{SCISSORS, PAPER, "Scissors snips Paper"},
+		{ROCK, SPOCK, "Rock is zapped by Spock"},
+		{SCISSORS, ROCK, "Scissors are blunted by Rock"},
+	}
+	for _, tt := range tests {
+		if got, err := tt.m1.Versus(tt.m2); got != tt.want || err != nil {
+			t.Errorf("%v.Versus(%v) = %q, %v, want %q", tt.m1, tt.m2, got, err, tt.want)
+		}
+	}
+}
+
+func TestPairingsReturnsACopy(t *testing.T) {
+	ps := Pairings()
+	ps[0] = nil
+	if got, err := SCISSORS.Versus(PAPER); got != "Scissors cuts Paper" || err != nil {
+		t.Errorf("after changing the copy, Versus gives %q, %v, want %q", got, err, "Scissors cuts Paper")
+	}
+}
+
+func TestLoadPairingsRejectsBadFiles(t *testing.T) {
+	lines := strings.Split(customPairings, "\n")
+	tests := []struct {
+		name, contents string
+	}{
+		{"missing", strings.Join(lines[:len(lines)-2], "\n")},     // no Rock vs Scissors
+		{"repeated", customPairings + lines[1] + "\n"},            // two Scissors vs Paper
+		{"backwards", customPairings + "Paper Scissors cuts cut"}, // Paper doesn't beat Scissors
+		{"short", customPairings + "Rock Scissors crushes"},
+		{"unknown", customPairings + "Rock Well falls fell"},
+	}
+	for _, tt := range tests {
+		if _, err := LoadPairings(writeFile(t, tt.name, tt.contents)); err == nil {
+			t.Errorf("%v: LoadPairings succeeded, want an error", tt.name)
+		}
+	}
+}
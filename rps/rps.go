@@ -1,152 +1,257 @@
 package main
 
 import (
-	"errors"
+	"flag"
 	"fmt"
-	"log"
+	"io"
 	"math/rand"
+	"os"
+	"strconv"
+	"strings"
 	"time"
+
+	"github.com/jlacar/golang-learn/rps/engine"
 )
 
-type Move int
+var (
+	// Source of random moves
+	rng *rand.Rand
 
-const (
-	ROCK Move = iota
-	SPOCK
-	PAPER
-	LIZARD
-	SCISSORS
-	LAST_Move
+	// flag option variables
+	matches   int
+	showStats bool
+	narrate   time.Duration
+	pairsPath string
+	sentPath  string
+	verify    bool
+	matrix    bool
+	winGames  int
 )
 
-var moveNames = []string{
-	"Rock",
-	"Spock",
-	"Paper",
-	"Lizard",
-	"Scissors",
+func randomMove() engine.Move {
+	return engine.Move(rng.Intn(int(engine.LAST_Move)))
 }
 
-type MatchUp struct {
-	p1, p2 Move
-	w, l   string
+func randomMatches(n int) {
+	for i := 0; i < n; i++ {
+		showVersus(randomMove(), randomMove())
+	}
 }
 
-/*
-http://bigbangtheory.wikia.com/wiki/Rock_Paper_Scissors_Lizard_Spock
-
-Scissors cut Paper
-Paper covers Rock
-Rock crushes Lizard
-Lizard poisons Spock
-Spock smashes Scissors
-Scissors decapitates Lizard
-Lizard eats Paper
-Paper disproves Spock
-Spock vaporizes Rock
-(and as it always has) Rock crushes Scissors
+// showVersus shows the result of m1 against m2, or warns about it and
+// carries on if there is no pairing for them.
+func showVersus(m1, m2 engine.Move) {
+	result, err := m1.Versus(m2)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		return
+	}
+	fmt.Println(result)
+}
 
-*/
+// Stats are the tallies of the outcomes of a number of matchups.
+type Stats struct {
+	Matches, P1Wins, P2Wins, Ties int
 
-var pairings = []*MatchUp{
-	&MatchUp{SCISSORS, PAPER, "cuts", "cut"},
-	&MatchUp{PAPER, ROCK, "covers", "covered"},
-	&MatchUp{ROCK, LIZARD, "crushes", "crushed"},
-	&MatchUp{LIZARD, SPOCK, "poisons", "poisoned"},
-	&MatchUp{SPOCK, SCISSORS, "smashes", "smashed"},
-	&MatchUp{SCISSORS, LIZARD, "decapitates", "decapitated"},
-	&MatchUp{LIZARD, PAPER, "eats", "eaten"},
-	&MatchUp{PAPER, SPOCK, "disproves", "disproved"},
-	&MatchUp{SPOCK, ROCK, "vaporizes", "vaporized"},
-	&MatchUp{ROCK, SCISSORS, "crushes", "crushed"},
+	// Chosen counts how often each move was played and Wins counts
+	// how often each move won, both indexed by Move.
+	Chosen, Wins [engine.LAST_Move]int
 }
 
-func (m *MatchUp) WinResult() string {
-	return fmt.Sprintf("%v %v %v", m.p1, m.w, m.p2)
+// randomStats plays n random matchups and tallies the outcomes.
+func randomStats(n int) (s Stats) {
+	for i := 0; i < n; i++ {
+		p1, p2 := randomMove(), randomMove()
+		s.Matches++
+		s.Chosen[p1]++
+		s.Chosen[p2]++
+		switch {
+		case p1.Beats(p2):
+			s.P1Wins++
+			s.Wins[p1]++
+		case p2.Beats(p1):
+			s.P2Wins++
+			s.Wins[p2]++
+		default:
+			s.Ties++
+		}
+	}
+	return
 }
 
-func (m *MatchUp) LoseResult() string {
-	tobe := "is"
-	if m.p2 == SCISSORS {
-		tobe = "are"
+// mostWins returns the move that won the most matchups.
+func (s Stats) mostWins() (best engine.Move) {
+	for m := engine.Move(0); m.NotLast(); m++ {
+		if s.Wins[m] > s.Wins[best] {
+			best = m
+		}
 	}
-	return fmt.Sprintf("%v %v %v by %v", m.p2, tobe, m.l, m.p1)
+	return
 }
 
-func (m Move) String() string {
-	if m.InRange() {
-		return moveNames[m]
+func reportStats(s Stats) {
+	const format = "%-10s %7v %7v\n"
+	fmt.Printf("%v random matchups\n\n", s.Matches)
+	fmt.Printf("Player 1 wins: %v\nPlayer 2 wins: %v\nTies: %v\n\n", s.P1Wins, s.P2Wins, s.Ties)
+	fmt.Printf(format, "Move", "Chosen", "Won")
+	for m := engine.Move(0); m.NotLast(); m++ {
+		fmt.Printf(format, m, s.Chosen[m], s.Wins[m])
 	}
-	return ""
-}
+	fmt.Printf("\nMost wins: %v\n", s.mostWins())
 
-func (m Move) NotLast() bool {
-	return m < LAST_Move
+	chi2 := s.choiceChiSquare()
+	verdict := "consistent with"
+	if chi2 > uniformChiSquare {
+		verdict = "NOT consistent with"
+	}
+	fmt.Printf("Chi-square of moves chosen: %.2f, %v every move being equally likely\n", chi2, verdict)
 }
 
-func (m Move) InRange() bool {
-	return m >= 0 && m.NotLast()
+// winRates returns the fraction of the times each move was played that
+// it won. Ties count as plays that didn't win, so with every move beating
+// two of the five moves, each should win about 40% of the time.
+func (s Stats) winRates() map[engine.Move]float64 {
+	rates := make(map[engine.Move]float64, engine.LAST_Move)
+	for m := engine.Move(0); m.NotLast(); m++ {
+		if s.Chosen[m] > 0 {
+			rates[m] = float64(s.Wins[m]) / float64(s.Chosen[m])
+		}
+	}
+	return rates
 }
 
-func (m1 Move) Versus(m2 Move) string {
-	matchUp, err := findMatchUp(m1, m2)
-	if err != nil {
-		log.Fatal(err)
+// reportWinRates plays n random matchups and reports the win rate of
+// each move.
+func reportWinRates(n int) {
+	s := randomStats(n)
+	rates := s.winRates()
+	fmt.Printf("Win rates over %v random matchups (ties count as not winning)\n\n", n)
+	for m := engine.Move(0); m.NotLast(); m++ {
+		fmt.Printf("%-10s %6.1f%%\n", m, 100*rates[m])
 	}
-	if m1 == m2 || m1.Beats(m2) {
-		return matchUp.WinResult()
-	}
-	return matchUp.LoseResult()
 }
 
-func (m1 Move) Beats(m2 Move) bool {
-	return m1 != m2 && (m1-m2+LAST_Move)%LAST_Move <= 2
+// uniformChiSquare is the chi-square value, for the 4 degrees of freedom
+// of the five moves, that is exceeded by chance only 0.1% of the time when
+// every move is equally likely.
+const uniformChiSquare = 18.467
+
+// choiceChiSquare returns the chi-square statistic of how often each move
+// was chosen in the matchups compared to every move being equally likely.
+func (s Stats) choiceChiSquare() float64 {
+	return chiSquare(s.Chosen)
+}
+
+// sampleMoves picks n random moves the same way randomMove does, using a
+// source seeded with seed so that the counts of each move it returns are
+// the same every time.
+func sampleMoves(seed int64, n int) (counts [engine.LAST_Move]int) {
+	saved := rng
+	defer func() { rng = saved }()
+	rng = rand.New(rand.NewSource(seed))
+	for i := 0; i < n; i++ {
+		counts[randomMove()]++
+	}
+	return
 }
 
-func findMatchUp(p1, p2 Move) (*MatchUp, error) {
-	if p1 == p2 {
-		return &MatchUp{p1: p1, p2: p2, w: "ties"}, nil
+// chiSquare returns the chi-square statistic of the counts of each move
+// compared to every move being counted equally often. A move that can
+// never be picked makes it far larger than uniformChiSquare.
+func chiSquare(counts [engine.LAST_Move]int) float64 {
+	total := 0
+	for _, n := range counts {
+		total += n
 	}
-	for _, m := range pairings {
-		if m.p1 == p1 && m.p2 == p2 || m.p1 == p2 && m.p2 == p1 {
-			return m, nil
-		}
+	if total == 0 {
+		return 0
 	}
-	return nil, errors.New(fmt.Sprintf("No pairing found for %v vs %v", p1, p2))
+	expected := float64(total) / float64(engine.LAST_Move)
+	chi2 := 0.0
+	for _, n := range counts {
+		d := float64(n) - expected
+		chi2 += d * d / expected
+	}
+	return chi2
 }
 
-func randomMove() Move {
-	return Move(rand.Intn(int(LAST_Move)))
+// verifyMoveSeed seeds the random moves picked by verifyMoves so that
+// its result does not change from one run to the next.
+const verifyMoveSeed = 1
+
+// minVerifyMoves is the fewest random moves that verifyMoves picks for
+// the -verify option. With fewer, the chi-square test of how they are
+// spread over the five moves doesn't mean much.
+const minVerifyMoves = 1000
+
+// verifyMoves picks n random moves and reports whether every move was
+// picked about as often as the others.
+func verifyMoves(n int) bool {
+	counts := sampleMoves(verifyMoveSeed, n)
+	for m := engine.Move(0); m.NotLast(); m++ {
+		fmt.Printf("%-10s %7v\n", m, counts[m])
+	}
+	chi2 := chiSquare(counts)
+	fmt.Printf("\nChi-square: %.2f (limit %v)\n", chi2, uniformChiSquare)
+	if chi2 > uniformChiSquare {
+		fmt.Println("Random moves are NOT evenly spread")
+		return false
+	}
+	fmt.Println("Random moves are evenly spread")
+	return true
 }
 
-func random10matches() {
-	for i := 0; i < 10; i++ {
-		fmt.Println(randomMove().Versus(randomMove()))
+// showMatrix writes a table of the outcome of every matchup from player
+// 1's point of view: W for a win, L for a loss, and T for a tie. Player 1's
+// moves are down the side and player 2's moves are across the top.
+func showMatrix(w io.Writer) {
+	const width = 10
+	fmt.Fprintf(w, "%-*s", width, "P1 \\ P2")
+	for p2 := engine.Move(0); p2.NotLast(); p2++ {
+		fmt.Fprintf(w, "%*v", width, p2)
+	}
+	fmt.Fprintln(w)
+	for p1 := engine.Move(0); p1.NotLast(); p1++ {
+		fmt.Fprintf(w, "%-*v", width, p1)
+		for p2 := engine.Move(0); p2.NotLast(); p2++ {
+			outcome := "T"
+			if p1.Beats(p2) {
+				outcome = "W"
+			} else if p2.Beats(p1) {
+				outcome = "L"
+			}
+			fmt.Fprintf(w, "%*v", width, outcome)
+		}
+		fmt.Fprintln(w)
 	}
 }
 
 func showAllMatchUps() {
-	for p1 := Move(0); p1.NotLast(); p1++ {
-		for p2 := Move(0); p2.NotLast(); p2++ {
-			fmt.Println(p1.Versus(p2))
+	for p1 := engine.Move(0); p1.NotLast(); p1++ {
+		for p2 := engine.Move(0); p2.NotLast(); p2++ {
+			showVersus(p1, p2)
 		}
 	}
 }
 
 func showWinningMatchUps() {
-	for p1 := Move(0); p1.NotLast(); p1++ {
+	for p1 := engine.Move(0); p1.NotLast(); p1++ {
 		for p2 := p1 + 1; p2.NotLast(); p2++ {
 			if p1.Beats(p2) {
-				fmt.Println(p1.Versus(p2))
+				showVersus(p1, p2)
 			} else if p2.Beats(p1) {
-				fmt.Println(p2.Versus(p1))
+				showVersus(p2, p1)
 			}
 		}
 	}
 }
 
 func SheldonExplains() {
+	pairings := engine.Pairings()
 	for i, vs := range pairings {
+		if i > 0 {
+			time.Sleep(narrate)
+		}
 		if i == len(pairings)-1 {
 			fmt.Print("...and as it always has, ")
 		}
@@ -155,18 +260,115 @@ func SheldonExplains() {
 }
 
 func init() {
-	rand.Seed(time.Now().UnixNano())
+	rng = rand.New(rand.NewSource(time.Now().UnixNano()))
+
+	flag.Usage = usage
+	flag.IntVar(&matches, "n", 10, "play `N` random matchups")
+	flag.BoolVar(&showStats, "stats", false, "only report statistics for the random matchups")
+	flag.StringVar(&pairsPath, "pairings", "", "read the matchups and their verbs from `file`")
+	flag.StringVar(&sentPath, "sentences", "", "read the win, lose, and tie sentence templates from `file`")
+	flag.IntVar(&winGames, "winrates", 0, "only report how often each move won when it was played over `N` random matchups")
+	flag.BoolVar(&matrix, "matrix", false, "only show a table of the outcomes of all the matchups for player 1")
+	flag.BoolVar(&verify, "verify", false, "check that random moves picked with a fixed seed are evenly spread\n\t"+
+		"over N*2 moves, or "+strconv.Itoa(minVerifyMoves)+" if that is more")
+	flag.DurationVar(&narrate, "narrate", 0, "pause for `delay` between each line of Sheldon's explanation")
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage: %v [-n] [-stats] [-narrate] [-pairings] [-sentences] [-matrix] [-winrates] [-verify] [play MOVE1 MOVE2]\n\n"+
+		"Options:\n\n", os.Args[0])
+	flag.PrintDefaults()
+	fmt.Fprintf(os.Stderr, "\nMoves: %v\n", strings.Join(engine.MoveNames(), ", "))
+}
+
+// play evaluates a single matchup between the moves named in args
+// and reports the result.
+func play(args []string) error {
+	if len(args) != 2 {
+		return fmt.Errorf("Expected 2 moves but got %v", len(args))
+	}
+	m1, err := engine.ParseMove(args[0])
+	if err != nil {
+		return err
+	}
+	m2, err := engine.ParseMove(args[1])
+	if err != nil {
+		return err
+	}
+	result, err := m1.Versus(m2)
+	if err != nil {
+		return err
+	}
+	fmt.Println(result)
+	return nil
 }
 
 func main() {
+	flag.Parse()
+
+	if pairsPath != "" {
+		ps, err := engine.LoadPairings(pairsPath)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		engine.SetPairings(ps)
+	}
+
+	if sentPath != "" {
+		s, err := engine.LoadSentences(sentPath)
+		if err == nil {
+			err = engine.SetSentences(s)
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	}
+
+	if flag.NArg() > 0 {
+		if flag.Arg(0) != "play" {
+			usage()
+			os.Exit(2)
+		}
+		if err := play(flag.Args()[1:]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			usage()
+			os.Exit(2)
+		}
+		return
+	}
+
+	if verify {
+		if !verifyMoves(max(2*matches, minVerifyMoves)) {
+			os.Exit(1)
+		}
+		return
+	}
+
+	if matrix {
+		showMatrix(os.Stdout)
+		return
+	}
+
+	if winGames > 0 {
+		reportWinRates(winGames)
+		return
+	}
+
+	if showStats {
+		reportStats(randomStats(matches))
+		return
+	}
+
 	fmt.Println("All matchups:")
 	showAllMatchUps()
 
 	fmt.Println("\nWinning matchups:")
 	showWinningMatchUps()
 
-	fmt.Println("\n10 random matchups:")
-	random10matches()
+	fmt.Printf("\n%v random matchups:\n", matches)
+	randomMatches(matches)
 
 	fmt.Println("\nSheldon explains Rock-Paper-Scissors-Lizard-Spock:")
 	SheldonExplains()
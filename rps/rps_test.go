@@ -0,0 +1,122 @@
+package main
+
+import (
+	"bytes"
+	"math"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/jlacar/golang-learn/rps/engine"
+)
+
+func TestRandomMovesAreEvenlySpread(t *testing.T) {
+	const n = 10000
+	counts := sampleMoves(verifyMoveSeed, n)
+	total := 0
+	for m := engine.Move(0); m.NotLast(); m++ {
+		if counts[m] == 0 {
+			t.Errorf("%v was never picked in %v moves", m, n)
+		}
+		total += counts[m]
+	}
+	if total != n {
+		t.Errorf("picked %v moves, want %v", total, n)
+	}
+	if chi2 := chiSquare(counts); chi2 > uniformChiSquare {
+		t.Errorf("chi-square of %v moves %v is %.2f, want at most %v", n, counts, chi2, uniformChiSquare)
+	}
+	if sampleMoves(verifyMoveSeed, n) != counts {
+		t.Errorf("the same seed picked different moves")
+	}
+}
+
+func TestChiSquareCatchesAMoveThatIsNeverPicked(t *testing.T) {
+	// as if randomMove could only pick the first four moves
+	counts := [engine.LAST_Move]int{2500, 2500, 2500, 2500, 0}
+	if chi2 := chiSquare(counts); chi2 <= uniformChiSquare {
+		t.Errorf("chi-square of %v is %.2f, want more than %v", counts, chi2, uniformChiSquare)
+	}
+}
+
+func TestShowMatrix(t *testing.T) {
+	var b bytes.Buffer
+	showMatrix(&b)
+	lines := strings.Split(strings.TrimSuffix(b.String(), "\n"), "\n")
+	if len(lines) != 1+int(engine.LAST_Move) {
+		t.Fatalf("got %v lines, want a heading and %v rows:\n%v", len(lines), engine.LAST_Move, b.String())
+	}
+	if want := "P1 \\ P2 Rock Spock Paper Lizard Scissors"; strings.Join(strings.Fields(lines[0]), " ") != want {
+		t.Errorf("heading is %q, want the moves like %q", lines[0], want)
+	}
+	// the outcome of each row's move against each column's move
+	outcome := map[string][]string{}
+	for _, line := range lines[1:] {
+		fields := strings.Fields(line)
+		outcome[fields[0]] = fields[1:]
+	}
+	tests := []struct {
+		p1, p2 engine.Move
+		want   string
+	}{
+		{engine.ROCK, engine.SCISSORS, "W"},
+		{engine.SCISSORS, engine.ROCK, "L"},
+		{engine.SPOCK, engine.SPOCK, "T"},
+		{engine.LIZARD, engine.SPOCK, "W"},
+		{engine.PAPER, engine.LIZARD, "L"},
+	}
+	for _, tt := range tests {
+		if got := outcome[tt.p1.String()][tt.p2]; got != tt.want {
+			t.Errorf("%v vs %v is %v, want %v", tt.p1, tt.p2, got, tt.want)
+		}
+	}
+}
+
+func TestRandomStats(t *testing.T) {
+	defer func(saved *rand.Rand) { rng = saved }(rng)
+	rng = rand.New(rand.NewSource(1))
+	const n = 1000
+	s := randomStats(n)
+	if s.Matches != n || s.P1Wins != 401 || s.P2Wins != 393 || s.Ties != 206 {
+		t.Errorf("%v matchups with seed 1 tallied %v, %v wins, %v losses, and %v ties, want %v, 401, 393, and 206",
+			n, s.Matches, s.P1Wins, s.P2Wins, s.Ties, n)
+	}
+	if s.P1Wins+s.P2Wins+s.Ties != n {
+		t.Errorf("%v wins, %v losses, and %v ties don't add up to %v matchups", s.P1Wins, s.P2Wins, s.Ties, n)
+	}
+	if want := [engine.LAST_Move]int{398, 426, 419, 368, 389}; s.Chosen != want {
+		t.Errorf("moves chosen %v, want %v", s.Chosen, want)
+	}
+	if want := [engine.LAST_Move]int{139, 174, 161, 160, 160}; s.Wins != want {
+		t.Errorf("moves won %v, want %v", s.Wins, want)
+	}
+}
+
+func TestWinRates(t *testing.T) {
+	s := Stats{
+		Chosen: [engine.LAST_Move]int{10, 4, 8, 0, 5},
+		Wins:   [engine.LAST_Move]int{4, 1, 6, 0, 5},
+	}
+	want := map[engine.Move]float64{engine.ROCK: 0.4, engine.SPOCK: 0.25, engine.PAPER: 0.75, engine.SCISSORS: 1}
+	got := s.winRates()
+	if len(got) != len(want) {
+		t.Errorf("got rates %v, want %v; a move that wasn't played has no rate", got, want)
+	}
+	for m, rate := range want {
+		if got[m] != rate {
+			t.Errorf("%v won %v of the time, want %v", m, got[m], rate)
+		}
+	}
+}
+
+func TestWinRatesOfRandomMatchups(t *testing.T) {
+	defer func(saved *rand.Rand) { rng = saved }(rng)
+	rng = rand.New(rand.NewSource(1))
+	// every move beats two of the five moves
+	rates := randomStats(100000).winRates()
+	for m := engine.Move(0); m.NotLast(); m++ {
+		if math.Abs(rates[m]-0.4) > 0.01 {
+			t.Errorf("%v won %.4f of the time, want about 0.4", m, rates[m])
+		}
+	}
+}
@@ -2,14 +2,42 @@
 package main
 
 import (
+	"flag"
 	"fmt"
+	"io"
+	"math/rand/v2"
 	"os"
+	"sort"
 	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
 )
 
-var primes []bool
+var (
+	primes []bool
+
+	// how far findPrimes has gotten through primes, for progress reports
+	sieved atomic.Int64
+
+	// flag option variables
+	showGaps   bool
+	factorN    int
+	singleLine bool
+	goldbachN  int
+	progress   bool
+	verify     bool
+	animate    bool
+)
 
 func findPrimes(max int) {
+	sieveWith(max, nil)
+}
+
+// sieveWith sieves the numbers up to max like findPrimes does, calling
+// crossedOut, if it isn't nil, after each prime's multiples have been
+// crossed out of primes.
+func sieveWith(max int, crossedOut func(p int)) {
 	primes = make([]bool, max+1)
 
 	for i := 2; i < len(primes); i++ {
@@ -17,32 +45,433 @@ func findPrimes(max int) {
 	}
 
 	for i := 2; i < len(primes); i++ {
+		sieved.Store(int64(i))
 		if primes[i] {
 			for j := 2 * i; j < len(primes); j += i {
 				primes[j] = false
 			}
+			if crossedOut != nil {
+				crossedOut(i)
+			}
 		}
 	}
+	sieved.Store(int64(len(primes)))
 }
 
-func listPrimes() {
-	count := 0
-	for i := 0; i < len(primes); i++ {
-		if primes[i] {
-			fmt.Printf("%4v, ", i)
-			count++
-			if count == 20 {
-				fmt.Print("\n")
-				count = 0
+const (
+	// animateLimit is the largest max that can be animated.
+	animateLimit = 400
+
+	// animateDelay is how long each step of the animation is shown.
+	animateDelay = 750 * time.Millisecond
+)
+
+// animateSieve sieves the numbers up to max, writing the grid of numbers
+// to w after each prime's multiples are crossed out. Numbers that are
+// still candidates are shown, crossed out numbers are shown as dots, and
+// the prime whose multiples were just crossed out is bracketed.
+func animateSieve(w io.Writer, max int) {
+	sieveWith(max, func(p int) {
+		if p*p > max {
+			// its multiples were already crossed out as multiples
+			// of smaller primes
+			return
+		}
+		fmt.Fprintf(w, "\nCrossed out the multiples of %v:\n", p)
+		for n := 1; n <= max; n++ {
+			switch {
+			case n == p:
+				fmt.Fprintf(w, "%5v", "["+strconv.Itoa(n)+"]")
+			case n < 2 || !primes[n]:
+				fmt.Fprintf(w, "%5v", ".")
+			default:
+				fmt.Fprintf(w, "%5v", n)
+			}
+			if n%10 == 0 {
+				fmt.Fprint(w, "\n")
+			}
+		}
+		if max%10 != 0 {
+			fmt.Fprint(w, "\n")
+		}
+		time.Sleep(animateDelay)
+	})
+}
+
+// progressInterval is how often the progress of the sieve is reported.
+const progressInterval = 250 * time.Millisecond
+
+// startProgress starts reporting how far through the numbers up to max
+// the sieve has gotten as a percentage on stderr. The returned function
+// stops the reports and returns once the last one has been written.
+// There is nothing to report if max is less than 1.
+func startProgress(max int) (stop func()) {
+	if max < 1 {
+		return func() {}
+	}
+	done := make(chan struct{})
+	finished := make(chan struct{})
+	report := func() {
+		fmt.Fprintf(os.Stderr, "\rSieving: %3d%%", sieved.Load()*100/int64(max+1))
+	}
+	go func() {
+		defer close(finished)
+		ticker := time.NewTicker(progressInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				report()
+			case <-done:
+				report()
+				fmt.Fprintln(os.Stderr)
+				return
 			}
 		}
+	}()
+	return func() {
+		close(done)
+		<-finished
+	}
+}
+
+// Primes returns the primes up to and including max.
+func Primes(max int) []int {
+	findPrimes(max)
+	var found []int
+	for i, isPrime := range primes {
+		if isPrime {
+			found = append(found, i)
+		}
+	}
+	return found
+}
+
+// PrimeGaps returns the differences between consecutive primes up to
+// and including max. The gap at index i is the distance between the
+// i-th and (i+1)-th primes.
+func PrimeGaps(max int) []int {
+	ps := Primes(max)
+	if len(ps) < 2 {
+		return nil
+	}
+	gaps := make([]int, len(ps)-1)
+	for i := range gaps {
+		gaps[i] = ps[i+1] - ps[i]
+	}
+	return gaps
+}
+
+// PrimesInRange returns the primes in the inclusive range [lo, hi].
+// A segmented sieve is used so that only the numbers in the range and the
+// base primes up to sqrt(hi) are allocated. A lo less than 2 is clamped to 2;
+// nil is returned if lo > hi.
+func PrimesInRange(lo, hi int) []int {
+	if lo < 2 {
+		lo = 2
+	}
+	if lo > hi {
+		return nil
+	}
+
+	segment := make([]bool, hi-lo+1)
+	for i := range segment {
+		segment[i] = true
+	}
+
+	for _, p := range basePrimes(isqrt(hi)) {
+		start := max(p*p, (lo+p-1)/p*p)
+		for j := start; j <= hi; j += p {
+			segment[j-lo] = false
+		}
+	}
+
+	var found []int
+	for i, isPrime := range segment {
+		if isPrime {
+			found = append(found, lo+i)
+		}
+	}
+	return found
+}
+
+// Factorize returns the prime factorization of n as a map of each prime
+// factor to its exponent. The sieved base primes up to sqrt(n) are tried
+// in turn; whatever remains greater than 1 after dividing them out is
+// itself prime. The map is empty for n < 2.
+func Factorize(n int) map[int]int {
+	factors := map[int]int{}
+	for _, p := range basePrimes(isqrt(n)) {
+		for n%p == 0 {
+			factors[p]++
+			n /= p
+		}
+	}
+	if n > 1 {
+		factors[n]++
+	}
+	return factors
+}
+
+// GoldbachPair returns two primes that add up to n, the smaller one
+// first, for an even n greater than 2. It reports false if n is odd or
+// too small, or if no such pair can be found.
+func GoldbachPair(n int) (int, int, bool) {
+	if n <= 2 || n%2 != 0 {
+		return 0, 0, false
+	}
+	findPrimes(n)
+	for p := 2; p <= n/2; p++ {
+		if primes[p] && primes[n-p] {
+			return p, n - p, true
+		}
+	}
+	return 0, 0, false
+}
+
+func showGoldbachPair(n int) {
+	p, q, ok := GoldbachPair(n)
+	if !ok {
+		fmt.Printf("No Goldbach pair for %v; it must be an even number greater than 2\n", n)
+		return
+	}
+	fmt.Printf("%v = %v + %v\n", n, p, q)
+}
+
+// formatFactors formats a factorization as a product of prime powers
+// in ascending order of the primes, e.g. "2^3 · 3^2 · 5".
+func formatFactors(factors map[int]int) string {
+	ps := make([]int, 0, len(factors))
+	for p := range factors {
+		ps = append(ps, p)
+	}
+	sort.Ints(ps)
+
+	terms := make([]string, len(ps))
+	for i, p := range ps {
+		terms[i] = strconv.Itoa(p)
+		if factors[p] > 1 {
+			terms[i] += "^" + strconv.Itoa(factors[p])
+		}
 	}
-	fmt.Print("\n")
+	return strings.Join(terms, " · ")
+}
+
+func showFactors(n int) {
+	factors := Factorize(n)
+	if len(factors) == 0 {
+		fmt.Printf("%v has no prime factors\n", n)
+		return
+	}
+	fmt.Printf("%v = %v\n", n, formatFactors(factors))
+}
+
+// isPrimeTrialDivision reports whether n is prime by trying to divide it
+// by 2 and every odd number up to its square root.
+func isPrimeTrialDivision(n int) bool {
+	if n < 2 {
+		return false
+	}
+	if n%2 == 0 {
+		return n == 2
+	}
+	for d := 3; d*d <= n; d += 2 {
+		if n%d == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+const (
+	// verifyAllLimit is the largest max for which every number is verified.
+	verifyAllLimit = 10000
+
+	// verifySamples is how many numbers are verified for larger maxes.
+	verifySamples = 1000
+)
+
+// verifySieve checks the results of the last sieve up to max against trial
+// division, either for every number or for a random sample of them if max
+// is large, and reports any disagreements.
+func verifySieve(w io.Writer, max int) {
+	var ns []int
+	if max <= verifyAllLimit {
+		for n := 0; n <= max; n++ {
+			ns = append(ns, n)
+		}
+	} else {
+		for range verifySamples {
+			ns = append(ns, rand.IntN(max+1))
+		}
+	}
+
+	disagreements := 0
+	for _, n := range ns {
+		if primes[n] != isPrimeTrialDivision(n) {
+			fmt.Fprintf(w, "Disagreement for %v: sieve says prime is %v, trial division says %v\n",
+				n, primes[n], !primes[n])
+			disagreements++
+		}
+	}
+	fmt.Fprintf(w, "Verified %v numbers up to %v by trial division: %v disagreements\n",
+		len(ns), max, disagreements)
+}
+
+// basePrimes returns the primes up to and including limit.
+func basePrimes(limit int) []int {
+	composite := make([]bool, limit+1)
+	var found []int
+	for i := 2; i <= limit; i++ {
+		if !composite[i] {
+			found = append(found, i)
+			for j := i * i; j <= limit; j += i {
+				composite[j] = true
+			}
+		}
+	}
+	return found
+}
+
+// isqrt returns the largest integer whose square does not exceed n.
+func isqrt(n int) int {
+	r := 0
+	for (r+1)*(r+1) <= n {
+		r++
+	}
+	return r
+}
+
+// listFormat is a way of listing primes.
+type listFormat int
+
+const (
+	columns listFormat = iota // padded, 20 to a line
+	oneline                   // comma-separated on a single line
+)
+
+// listPrimes writes the given primes to w in the given format.
+func listPrimes(w io.Writer, ps []int, format listFormat) {
+	if format == oneline {
+		strs := make([]string, len(ps))
+		for i, p := range ps {
+			strs[i] = strconv.Itoa(p)
+		}
+		fmt.Fprintln(w, strings.Join(strs, ","))
+		return
+	}
+
+	for i, p := range ps {
+		fmt.Fprintf(w, "%4v, ", p)
+		if (i+1)%20 == 0 {
+			fmt.Fprint(w, "\n")
+		}
+	}
+	fmt.Fprint(w, "\n")
+}
+
+// largestGap returns the index of the first of the largest of the gaps.
+func largestGap(gaps []int) int {
+	largest := 0
+	for i, g := range gaps {
+		if g > gaps[largest] {
+			largest = i
+		}
+	}
+	return largest
+}
+
+// showGapSummary reports the number of prime gaps up to max and the
+// largest gap found, along with the primes on either side of it.
+func showGapSummary(max int) {
+	gaps := PrimeGaps(max)
+	if len(gaps) == 0 {
+		fmt.Printf("No prime gaps up to %v\n", max)
+		return
+	}
+	ps := Primes(max)
+	largest := largestGap(gaps)
+	fmt.Printf("%v prime gaps up to %v\n", len(gaps), max)
+	fmt.Printf("Largest gap: %v (between %v and %v)\n",
+		gaps[largest], ps[largest], ps[largest+1])
+}
+
+func init() {
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %v [-gaps] [-oneline] [-progress] [-verify] max\n"+
+			"       %v -animate max\n"+
+			"       %v [-oneline] lo hi\n"+
+			"       %v -factor N\n"+
+			"       %v -goldbach N\n\n"+
+			"Options:\n\n", os.Args[0], os.Args[0], os.Args[0], os.Args[0], os.Args[0])
+		flag.PrintDefaults()
+	}
+
+	flag.BoolVar(&showGaps, "gaps", false, "summarize the gaps between primes up to max")
+	flag.IntVar(&factorN, "factor", 0, "print the prime factorization of `N`")
+	flag.IntVar(&goldbachN, "goldbach", 0, "print two primes that add up to the even number `N`")
+	flag.BoolVar(&progress, "progress", false, "report the progress of the sieve on stderr")
+	flag.BoolVar(&animate, "animate", false, "show the numbers up to max as the multiples of each prime are crossed out\n\t"+
+		"max can be at most "+strconv.Itoa(animateLimit))
+	flag.BoolVar(&verify, "verify", false, "check the sieve against trial division for every number up to max,\n\t"+
+		"or a random sample of them if max is over 10000")
+	flag.BoolVar(&singleLine, "oneline", false, "list the primes on a single line, separated by commas")
 }
 
 func main() {
-	max, _ := strconv.Atoi(os.Args[1])
+	flag.Parse()
 
-	findPrimes(max)
-	listPrimes()
+	// The options that take a number can be given 0, so look for the
+	// ones that were set rather than for a value other than 0.
+	set := map[string]bool{}
+	flag.Visit(func(f *flag.Flag) { set[f.Name] = true })
+
+	if set["factor"] {
+		showFactors(factorN)
+		return
+	}
+
+	if set["goldbach"] {
+		showGoldbachPair(goldbachN)
+		return
+	}
+
+	format := columns
+	if singleLine {
+		format = oneline
+	}
+
+	if flag.NArg() > 1 {
+		lo, _ := strconv.Atoi(flag.Arg(0))
+		hi, _ := strconv.Atoi(flag.Arg(1))
+		listPrimes(os.Stdout, PrimesInRange(lo, hi), format)
+		return
+	}
+
+	max, _ := strconv.Atoi(flag.Arg(0))
+
+	if animate {
+		if max > animateLimit {
+			fmt.Fprintf(os.Stderr, "Cannot animate more than %v numbers\n", animateLimit)
+			os.Exit(2)
+		}
+		animateSieve(os.Stdout, max)
+		return
+	}
+
+	if showGaps {
+		showGapSummary(max)
+		return
+	}
+
+	stopProgress := func() {}
+	if progress {
+		stopProgress = startProgress(max)
+	}
+	ps := Primes(max)
+	stopProgress()
+	listPrimes(os.Stdout, ps, format)
+	if verify {
+		verifySieve(os.Stdout, max)
+	}
 }
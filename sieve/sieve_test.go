@@ -0,0 +1,121 @@
+package main
+
+import (
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestPrimesInRange(t *testing.T) {
+	tests := []struct {
+		lo, hi int
+		want   []int
+	}{
+		{10, 30, []int{11, 13, 17, 19, 23, 29}},
+		{24, 28, nil},
+		{0, 10, []int{2, 3, 5, 7}},
+		{-5, 2, []int{2}},
+		{97, 97, []int{97}},
+		{30, 10, nil},
+	}
+	for _, tt := range tests {
+		if got := PrimesInRange(tt.lo, tt.hi); !slices.Equal(got, tt.want) {
+			t.Errorf("PrimesInRange(%v, %v) = %v, want %v", tt.lo, tt.hi, got, tt.want)
+		}
+	}
+}
+
+func TestPrimesInRangeMatchesPrimes(t *testing.T) {
+	ps := Primes(1000)
+	for _, r := range [][2]int{{2, 1000}, {500, 1000}, {900, 997}} {
+		var want []int
+		for _, p := range ps {
+			if p >= r[0] && p <= r[1] {
+				want = append(want, p)
+			}
+		}
+		if got := PrimesInRange(r[0], r[1]); !slices.Equal(got, want) {
+			t.Errorf("PrimesInRange(%v, %v) = %v, want %v", r[0], r[1], got, want)
+		}
+	}
+}
+
+func TestPrimeGaps(t *testing.T) {
+	want := []int{1, 2, 2, 4, 2, 4, 2, 4, 6, 2, 6}
+	if got := PrimeGaps(37); !slices.Equal(got, want) {
+		t.Errorf("PrimeGaps(37) = %v, want %v", got, want)
+	}
+	if got := PrimeGaps(2); got != nil {
+		t.Errorf("PrimeGaps(2) = %v, want nil", got)
+	}
+}
+
+func TestLargestPrimeGapBelow100(t *testing.T) {
+	ps := Primes(100)
+	gaps := PrimeGaps(100)
+	largest := largestGap(gaps)
+	if gaps[largest] != 8 || ps[largest] != 89 || ps[largest+1] != 97 {
+		t.Errorf("largest gap below 100 = %v between %v and %v, want 8 between 89 and 97",
+			gaps[largest], ps[largest], ps[largest+1])
+	}
+}
+
+func TestFactorize(t *testing.T) {
+	tests := []struct {
+		n    int
+		want map[int]int
+	}{
+		{0, map[int]int{}},
+		{1, map[int]int{}},
+		{97, map[int]int{97: 1}},
+		{128, map[int]int{2: 7}},
+		{360, map[int]int{2: 3, 3: 2, 5: 1}},
+		{2 * 2 * 1009, map[int]int{2: 2, 1009: 1}},
+	}
+	for _, tt := range tests {
+		if got := Factorize(tt.n); !maps.Equal(got, tt.want) {
+			t.Errorf("Factorize(%v) = %v, want %v", tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestFormatFactors(t *testing.T) {
+	if got, want := formatFactors(Factorize(360)), "2^3 · 3^2 · 5"; got != want {
+		t.Errorf("formatFactors(Factorize(360)) = %q, want %q", got, want)
+	}
+}
+
+func TestListPrimesOneline(t *testing.T) {
+	var b strings.Builder
+	listPrimes(&b, Primes(20), oneline)
+	if got, want := b.String(), "2,3,5,7,11,13,17,19\n"; got != want {
+		t.Errorf("listPrimes(Primes(20), oneline) wrote %q, want %q", got, want)
+	}
+}
+
+func TestGoldbachPair(t *testing.T) {
+	for _, n := range []int{4, 6, 28, 100, 1000, 9998} {
+		p, q, ok := GoldbachPair(n)
+		if !ok || p+q != n || p > q || !isPrimeTrialDivision(p) || !isPrimeTrialDivision(q) {
+			t.Errorf("GoldbachPair(%v) = %v, %v, %v, want two primes that add up to %v", n, p, q, ok, n)
+		}
+	}
+	if p, q, _ := GoldbachPair(100); p != 3 || q != 97 {
+		t.Errorf("GoldbachPair(100) = %v, %v, want 3, 97", p, q)
+	}
+	for _, n := range []int{-4, 0, 1, 2, 7, 99} {
+		if p, q, ok := GoldbachPair(n); ok {
+			t.Errorf("GoldbachPair(%v) = %v, %v, true, want false", n, p, q)
+		}
+	}
+}
+
+func TestSieveAgreesWithTrialDivision(t *testing.T) {
+	findPrimes(10000)
+	for n := 0; n <= 10000; n++ {
+		if primes[n] != isPrimeTrialDivision(n) {
+			t.Errorf("sieve says %v is prime is %v, trial division says %v", n, primes[n], !primes[n])
+		}
+	}
+}